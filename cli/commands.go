@@ -7,6 +7,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+
+	"github.com/traefik/paerser/parser"
 )
 
 // Command structure contains program/command information (command name and description).
@@ -19,8 +21,13 @@ type Command struct {
 	CustomHelpFunc func(io.Writer, *Command) error
 	Hidden         bool
 	// AllowArg if not set, disallows any argument that is not a known command or a sub-command.
-	AllowArg    bool
-	subCommands []*Command
+	AllowArg bool
+	// MergeResources if set, merges the configurations loaded by all the resources,
+	// instead of stopping at the first resource which is found.
+	// The precedence is: defaults < file < env < flags (see NodeLoader).
+	MergeResources bool
+	subCommands    []*Command
+	sources        map[string]*parser.Source
 }
 
 // AddCommand Adds a sub command.
@@ -46,6 +53,12 @@ func (c *Command) PrintHelp(w io.Writer) error {
 	return PrintHelp(w, c)
 }
 
+// Sources returns the source of each configuration value, indexed by the path of the value.
+// The sources are only resolved when the resources are merged (see MergeResources).
+func (c *Command) Sources() map[string]*parser.Source {
+	return c.sources
+}
+
 // Execute Executes a command.
 func Execute(cmd *Command) error {
 	return execute(cmd, os.Args, true)
@@ -120,6 +133,14 @@ func run(cmd *Command, args []string) error {
 		return cmd.Run(args)
 	}
 
+	if cmd.MergeResources {
+		if err := mergeResources(cmd, args); err != nil {
+			return err
+		}
+
+		return cmd.Run(args)
+	}
+
 	for _, resource := range cmd.Resources {
 		done, err := resource.Load(args, cmd)
 		if err != nil {
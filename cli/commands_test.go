@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/parser"
 )
 
 func TestCommand_AddCommand(t *testing.T) {
@@ -777,6 +779,10 @@ func Test_execute_configuration_file(t *testing.T) {
 			desc: "configfile arg in lower case",
 			args: []string{"", "sub1", "--configfile=./fixtures/config.toml"},
 		},
+		{
+			desc: "configFile arg with a directory",
+			args: []string{"", "sub1", "--configFile=./fixtures/conf.d"},
+		},
 	}
 
 	for _, test := range testCases {
@@ -943,3 +949,134 @@ func Test_execute_subCommands(t *testing.T) {
 	err = execute(rootCmd, []string{"", "test", "subtest", "subsubtest", "subsubsubtest", "--help"}, true)
 	require.NoError(t, err)
 }
+
+func Test_execute_mergeResources(t *testing.T) {
+	t.Setenv("TRAEFIK_FII", "env")
+	t.Setenv("TRAEFIK_SERVERS_ONE_WEIGHT", "2")
+	t.Setenv("TRAEFIK_SERVERS_THREE_URL", "http://three")
+
+	element := &Ya{
+		Fee: "default",
+		Foo: "default",
+	}
+
+	fileLoader := &FileLoader{
+		ConfigFileFlag: "configFile",
+		BasePaths:      []string{"./traefik"},
+	}
+
+	cmd := &Command{
+		Name:           "root",
+		Configuration:  element,
+		Resources:      []ResourceLoader{&FlagLoader{}, &EnvLoader{}, fileLoader},
+		MergeResources: true,
+		Run: func(_ []string) error {
+			return nil
+		},
+	}
+
+	args := []string{"", "--configFile=./fixtures/merge.toml", "--fuu=flag", "--names=c,d"}
+
+	err := execute(cmd, args, true)
+	require.NoError(t, err)
+
+	expected := &Ya{
+		ConfigFile: "./fixtures/merge.toml",
+		Foo:        "file",
+		Fii:        "env",
+		Fuu:        "flag",
+		Fee:        "default",
+		Names:      []string{"c", "d"},
+		Servers: map[string]*Yu{
+			"one":   {URL: "http://one", Weight: 2},
+			"two":   {URL: "http://two"},
+			"three": {URL: "http://three"},
+		},
+	}
+	assert.Equal(t, expected, element)
+
+	filePath, err := filepath.Abs("./fixtures/merge.toml")
+	require.NoError(t, err)
+
+	assert.Equal(t, filePath, fileLoader.GetFilename())
+
+	sources := cmd.Sources()
+	assert.Equal(t, &parser.Source{Kind: parser.SourceFile, Name: filePath, Line: 1, Column: 1}, sources["traefik.foo"])
+	assert.Equal(t, &parser.Source{Kind: parser.SourceEnv, Name: "TRAEFIK_FII"}, sources["traefik.fii"])
+	assert.Equal(t, &parser.Source{Kind: parser.SourceFlag, Name: "--fuu", Index: 2}, sources["traefik.fuu"])
+	assert.Equal(t, &parser.Source{Kind: parser.SourceFlag, Name: "--names", Index: 3}, sources["traefik.names"])
+	assert.Equal(t, &parser.Source{Kind: parser.SourceFile, Name: filePath, Line: 7, Column: 3}, sources["traefik.servers.one.url"])
+	assert.Equal(t, &parser.Source{Kind: parser.SourceEnv, Name: "TRAEFIK_SERVERS_ONE_WEIGHT"}, sources["traefik.servers.one.weight"])
+	assert.Equal(t, &parser.Source{Kind: parser.SourceEnv, Name: "TRAEFIK_SERVERS_THREE_URL"}, sources["traefik.servers.three.url"])
+	assert.NotContains(t, sources, "traefik.fee")
+}
+
+func Test_execute_mergeResources_error(t *testing.T) {
+	cmd := &Command{
+		Name:           "root",
+		Configuration:  &Ya{},
+		Resources:      []ResourceLoader{&FlagLoader{}},
+		MergeResources: true,
+		Run: func(_ []string) error {
+			return nil
+		},
+	}
+
+	err := execute(cmd, []string{"", "--servers.one.weight=foo"}, true)
+	require.Error(t, err)
+
+	var dErr *parser.DecodeError
+	require.ErrorAs(t, err, &dErr)
+	assert.Equal(t, "traefik.servers.one.weight", dErr.Path)
+	assert.Equal(t, "flag --servers.one.weight (argument 1)", dErr.Origin)
+}
+
+func Test_execute_mergeResources_fileSliceOfStructs(t *testing.T) {
+	type Server struct {
+		URL    string
+		Weight int
+	}
+
+	type Config struct {
+		ConfigFile string
+		Name       string
+		Servers    []Server `label-slice-as-struct:"server"`
+	}
+
+	filePath := filepath.Join(t.TempDir(), "cfg.toml")
+	content := `
+[[servers]]
+  url = "http://one"
+  weight = 1
+
+[[servers]]
+  url = "http://two"
+`
+	err := os.WriteFile(filePath, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	element := &Config{}
+
+	cmd := &Command{
+		Name:           "root",
+		Configuration:  element,
+		Resources:      []ResourceLoader{&FlagLoader{}, &FileLoader{ConfigFileFlag: "configFile", BasePaths: []string{"./traefik"}}},
+		MergeResources: true,
+		Run: func(_ []string) error {
+			return nil
+		},
+	}
+
+	err = execute(cmd, []string{"", "--configFile=" + filePath, "--name=flag"}, true)
+	require.NoError(t, err)
+
+	expected := &Config{
+		ConfigFile: filePath,
+		Name:       "flag",
+		Servers: []Server{
+			{URL: "http://one", Weight: 1},
+			{URL: "http://two"},
+		},
+	}
+	assert.Equal(t, expected, element)
+}
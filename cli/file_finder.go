@@ -4,11 +4,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/traefik/paerser/file"
 )
 
 // Finder holds a list of file paths.
 type Finder struct {
-	BasePaths  []string
+	BasePaths []string
+	// Extensions are the extensions of the files looked up in BasePaths,
+	// the extensions of the registered file formats by default (see file.Extensions).
 	Extensions []string
 }
 
@@ -40,8 +44,13 @@ func (f Finder) getPaths(configFile string) []string {
 		paths = append(paths, configFile)
 	}
 
+	extensions := f.Extensions
+	if len(extensions) == 0 {
+		extensions = file.Extensions()
+	}
+
 	for _, basePath := range f.BasePaths {
-		for _, ext := range f.Extensions {
+		for _, ext := range extensions {
 			paths = append(paths, basePath+"."+ext)
 		}
 	}
@@ -158,3 +158,12 @@ func TestFinder_getPaths(t *testing.T) {
 		})
 	}
 }
+
+func TestFinder_getPaths_defaultExtensions(t *testing.T) {
+	finder := Finder{BasePaths: []string{"./traefik"}}
+
+	paths := finder.getPaths("")
+
+	expected := []string{"./traefik.toml", "./traefik.yaml", "./traefik.yml", "./traefik.json"}
+	assert.Equal(t, expected, paths)
+}
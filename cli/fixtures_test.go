@@ -23,3 +23,18 @@ func (y *Yi) SetDefaults() {
 	y.Foo = "foo"
 	y.Fii = "fii"
 }
+
+type Ya struct {
+	ConfigFile string
+	Foo        string
+	Fii        string
+	Fuu        string
+	Fee        string
+	Names      []string
+	Servers    map[string]*Yu
+}
+
+type Yu struct {
+	URL    string
+	Weight int
+}
@@ -28,7 +28,7 @@ Flag's usage: {{ .Cmd.Name }} [--flag=flag_argument] [-f [flag_argument]]	# set
 
 Flags:
 {{- range $i, $flag := .Flags }}
-	--{{ SliceIndexN $flag.Name }}  {{if ne $flag.Name "global.sendanonymoususage"}}(Default: "{{ $flag.Default}}"){{end}}
+	--{{ SliceIndexN $flag.Name }}  {{if ne $flag.Name "global.sendanonymoususage"}}(Default: "{{ $flag.Default}}"){{end}}{{if $flag.Constraints }}  (Constraints: {{ $flag.Constraints }}){{end}}
 {{if $flag.Description }}		{{ wrapWith 80 "\n\t\t" $flag.Description }}
 {{else}}
 {{- end}}
@@ -49,7 +49,9 @@ func isHelp(args []string) bool {
 func PrintHelp(w io.Writer, cmd *Command) error {
 	var flags []parser.Flat
 	if cmd.Configuration != nil {
-		generator.Generate(cmd.Configuration)
+		if err := generator.GenerateWithError(cmd.Configuration); err != nil {
+			return err
+		}
 
 		var err error
 		flags, err = flag.Encode(cmd.Configuration)
@@ -192,6 +192,41 @@ Flags:
 
     --foo[n].field  (Default: "")
 
+`,
+		},
+		{
+			desc: "constraints and defaults",
+			command: func() *Command {
+				element := &struct {
+					Foo string `description:"Foo description" paerser-validate:"required,oneof=a b"`
+					Fii int    `description:"Fii description" default:"10" paerser-validate:"min=1,max=100"`
+				}{}
+
+				return &Command{
+					Name:          "root",
+					Description:   "Description for root",
+					Configuration: element,
+					Run: func(_ []string) error {
+						return nil
+					},
+				}
+			}(),
+			expected: `root    Description for root
+
+Usage: root [command] [flags] [arguments]
+
+Use "root [command] --help" for help on any command.
+
+Flag's usage: root [--flag=flag_argument] [-f [flag_argument]]    # set flag_argument to flag(s)
+          or: root [--flag[=true|false| ]] [-f [true|false| ]]    # set true/false to boolean flag(s)
+
+Flags:
+    --fii  (Default: "10")  (Constraints: min=1, max=100)
+        Fii description
+
+    --foo  (Default: "")  (Constraints: required, oneof=a b)
+        Foo description
+
 `,
 		},
 	}
@@ -209,3 +244,27 @@ Flags:
 		})
 	}
 }
+
+func TestPrintHelp_sensitive(t *testing.T) {
+	cmd := &Command{
+		Name:        "root",
+		Description: "Description for root",
+		Configuration: &struct {
+			Password string `description:"Password description" sensitive:"true" default:"admin"`
+			User     string `description:"User description"`
+		}{
+			User: "root",
+		},
+		Run: func(_ []string) error {
+			return nil
+		},
+	}
+
+	var buffer bytes.Buffer
+	err := PrintHelp(&buffer, cmd)
+	require.NoError(t, err)
+
+	assert.Contains(t, buffer.String(), `--password  (Default: "<redacted>")`)
+	assert.Contains(t, buffer.String(), `--user  (Default: "root")`)
+	assert.NotContains(t, buffer.String(), "admin")
+}
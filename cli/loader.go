@@ -1,7 +1,18 @@
 package cli
 
+import "github.com/traefik/paerser/parser"
+
 // ResourceLoader is a configuration resource loader.
 type ResourceLoader interface {
 	// Load populates cmd.Configuration, optionally using args to do so.
 	Load(args []string, cmd *Command) (bool, error)
 }
+
+// NodeLoader is a configuration resource loader which is able to load the configuration as a tree of untyped nodes.
+// It is used by the merge mode of the command (see Command.MergeResources).
+type NodeLoader interface {
+	ResourceLoader
+	// LoadNode returns the configuration as a tree of untyped nodes, holding the source of their values,
+	// optionally using args to do so. It returns a nil node if the resource is not found.
+	LoadNode(args []string, cmd *Command) (*parser.Node, error)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/traefik/paerser/env"
+	"github.com/traefik/paerser/parser"
 )
 
 // EnvLoader loads a configuration from all the environment variables prefixed with Prefix (default: "TRAEFIK_").
@@ -14,10 +15,7 @@ type EnvLoader struct {
 
 // Load loads the command's configuration from the environment variables.
 func (e *EnvLoader) Load(_ []string, cmd *Command) (bool, error) {
-	prefix := env.DefaultNamePrefix
-	if e.Prefix != "" {
-		prefix = e.Prefix
-	}
+	prefix := e.getPrefix()
 
 	vars := env.FindPrefixedEnvVars(os.Environ(), prefix, cmd.Configuration)
 	if len(vars) == 0 {
@@ -25,8 +23,33 @@ func (e *EnvLoader) Load(_ []string, cmd *Command) (bool, error) {
 	}
 
 	if err := env.Decode(vars, prefix, cmd.Configuration); err != nil {
-		return false, fmt.Errorf("failed to decode configuration from environment variables: %w ", err)
+		return false, fmt.Errorf("failed to decode configuration from environment variables: %w", err)
 	}
 
 	return true, nil
 }
+
+// LoadNode loads the command's configuration from the environment variables as a tree of untyped nodes.
+func (e *EnvLoader) LoadNode(_ []string, cmd *Command) (*parser.Node, error) {
+	prefix := e.getPrefix()
+
+	vars := env.FindPrefixedEnvVars(os.Environ(), prefix, cmd.Configuration)
+	if len(vars) == 0 {
+		return nil, nil
+	}
+
+	node, err := env.DecodeToNode(vars, prefix, cmd.Configuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode configuration from environment variables: %w", err)
+	}
+
+	return node, nil
+}
+
+func (e *EnvLoader) getPrefix() string {
+	if e.Prefix != "" {
+		return e.Prefix
+	}
+
+	return env.DefaultNamePrefix
+}
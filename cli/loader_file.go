@@ -2,6 +2,7 @@ package cli
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 
@@ -11,11 +12,14 @@ import (
 )
 
 // FileLoader loads a configuration from a file.
+// The ConfigFileFlag flag can point to a directory: its configuration files are merged in the lexical order.
 type FileLoader struct {
 	ConfigFileFlag string
 	filename       string
 	BasePaths      []string
-	Extensions     []string
+	// Extensions are the extensions of the configuration files looked up in BasePaths,
+	// the extensions of the registered file formats by default (see file.Extensions).
+	Extensions []string
 }
 
 // GetFilename returns the configuration file if any.
@@ -25,65 +29,72 @@ func (f *FileLoader) GetFilename() string {
 
 // Load loads the command's configuration from a file either specified with the ConfigFileFlag flag, or from default locations.
 func (f *FileLoader) Load(args []string, cmd *Command) (bool, error) {
-	ref, err := flag.Parse(args, cmd.Configuration)
+	filePath, err := f.findConfigFile(args, cmd)
 	if err != nil {
-		_ = cmd.PrintHelp(os.Stdout)
 		return false, err
 	}
 
-	var configFileFlag string
-	if f.ConfigFileFlag == "" {
-		return false, errors.New("missing config file flag")
-	}
-
-	configFileFlag = parser.DefaultRootName + "." + f.ConfigFileFlag
-	if _, ok := ref[strings.ToLower(configFileFlag)]; ok {
-		configFileFlag = parser.DefaultRootName + "." + strings.ToLower(f.ConfigFileFlag)
+	if filePath == "" {
+		return false, nil
 	}
 
-	configFile, err := f.loadConfigFiles(ref[configFileFlag], cmd.Configuration)
-	if err != nil {
-		return false, err
+	if err = file.Decode(filePath, cmd.Configuration); err != nil {
+		return false, fmt.Errorf("failed to decode configuration from file: %w", err)
 	}
 
-	f.filename = configFile
-
-	if configFile == "" {
-		return false, nil
-	}
+	f.filename = filePath
 
 	return true, nil
 }
 
-// loadConfigFiles tries to decode the given configuration file and all default locations for the configuration file.
-// It stops as soon as decoding one of them is successful.
-func (f *FileLoader) loadConfigFiles(configFile string, element interface{}) (string, error) {
-	extensions := []string{"toml", "yaml", "yml"}
-	if len(f.Extensions) != 0 {
-		extensions = f.Extensions
+// LoadNode loads the command's configuration from a file as a tree of untyped nodes.
+// The file is either specified with the ConfigFileFlag flag, or found in default locations.
+func (f *FileLoader) LoadNode(args []string, cmd *Command) (*parser.Node, error) {
+	filePath, err := f.findConfigFile(args, cmd)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(f.BasePaths) == 0 {
-		return "", errors.New("missing base paths")
+	if filePath == "" {
+		return nil, nil
 	}
 
-	finder := Finder{
-		BasePaths:  f.BasePaths,
-		Extensions: extensions,
+	node, err := file.DecodeToNode(filePath, cmd.Configuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode configuration from file: %w", err)
 	}
 
-	filePath, err := finder.Find(configFile)
+	f.filename = filePath
+
+	return node, nil
+}
+
+// findConfigFile returns the path of the configuration file, either specified with the ConfigFileFlag flag,
+// or the first one found in the default locations.
+func (f *FileLoader) findConfigFile(args []string, cmd *Command) (string, error) {
+	ref, err := flag.Parse(args, cmd.Configuration)
 	if err != nil {
+		_ = cmd.PrintHelp(os.Stdout)
 		return "", err
 	}
 
-	if len(filePath) == 0 {
-		return "", nil
+	if f.ConfigFileFlag == "" {
+		return "", errors.New("missing config file flag")
 	}
 
-	if err = file.Decode(filePath, element); err != nil {
-		return "", err
+	configFileFlag := parser.DefaultRootName + "." + f.ConfigFileFlag
+	if _, ok := ref[strings.ToLower(configFileFlag)]; ok {
+		configFileFlag = parser.DefaultRootName + "." + strings.ToLower(f.ConfigFileFlag)
+	}
+
+	if len(f.BasePaths) == 0 {
+		return "", errors.New("missing base paths")
+	}
+
+	finder := Finder{
+		BasePaths:  f.BasePaths,
+		Extensions: f.Extensions,
 	}
 
-	return filePath, nil
+	return finder.Find(ref[configFileFlag])
 }
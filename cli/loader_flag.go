@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/traefik/paerser/flag"
+	"github.com/traefik/paerser/parser"
 )
 
 // FlagLoader loads configuration from flags.
@@ -21,3 +22,17 @@ func (*FlagLoader) Load(args []string, cmd *Command) (bool, error) {
 
 	return true, nil
 }
+
+// LoadNode loads the command's configuration from flag arguments as a tree of untyped nodes.
+func (*FlagLoader) LoadNode(args []string, cmd *Command) (*parser.Node, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	node, err := flag.DecodeToNode(args, cmd.Configuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode configuration from flags: %w", err)
+	}
+
+	return node, nil
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/traefik/paerser/parser"
+)
+
+// rawSliceSeparator is the separator used by the file resources to encode the typed slices,
+// the values of the other resources are also supported with this separator.
+const rawSliceSeparator = "║"
+
+// precedences defines the precedence of the resources in the merge mode, by kind of source.
+// The values of a resource override the values of the resources with a lower precedence.
+var precedences = map[parser.SourceKind]int{
+	parser.SourceFile:  1,
+	parser.SourceLabel: 2,
+	parser.SourceEnv:   3,
+	parser.SourceFlag:  4,
+}
+
+// mergeResources loads all the resources of the command, merges them, and decodes the result into the configuration.
+// The defaults are the values of the configuration before the loading, they have the lowest precedence.
+func mergeResources(cmd *Command, args []string) error {
+	var nodes []*parser.Node
+	for _, resource := range cmd.Resources {
+		loader, ok := resource.(NodeLoader)
+		if !ok {
+			// the resources which cannot be merged are loaded directly in the configuration,
+			// so their values are overridden by the merged resources.
+			if _, err := resource.Load(args, cmd); err != nil {
+				return err
+			}
+			continue
+		}
+
+		node, err := loader.LoadNode(args, cmd)
+		if err != nil {
+			return err
+		}
+
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return getPrecedence(nodes[i]) < getPrecedence(nodes[j])
+	})
+
+	var root *parser.Node
+	for _, node := range nodes {
+		node.Name = parser.DefaultRootName
+
+		err := parser.AddMetadata(cmd.Configuration, node, getMetadataOpts(node))
+		if err != nil {
+			return fmt.Errorf("failed to decode configuration: %w", withOrigins(err))
+		}
+
+		root, err = parser.MergeNodes(root, node, parser.MergeOpts{})
+		if err != nil {
+			return fmt.Errorf("failed to merge configuration: %w", err)
+		}
+	}
+
+	if root == nil {
+		return nil
+	}
+
+	// AllowSliceAsStruct applies to the nodes of the label, environment, and flag resources:
+	// the slices of the file resources are lists of indexed elements (see getMetadataOpts), filled as lists.
+	fillerOpts := parser.FillerOpts{AllowSliceAsStruct: true, RawSliceSeparator: rawSliceSeparator}
+
+	if err := parser.Fill(cmd.Configuration, root, fillerOpts); err != nil {
+		return fmt.Errorf("failed to decode configuration: %w", withOrigins(err))
+	}
+
+	if err := parser.Validate(cmd.Configuration, root.Name); err != nil {
+		return fmt.Errorf("failed to decode configuration: %w", err)
+	}
+
+	cmd.sources = make(map[string]*parser.Source)
+	getSources(cmd.sources, root, root.Name)
+
+	return nil
+}
+
+// getMetadataOpts returns the metadata options related to the kind of resource of the node.
+func getMetadataOpts(node *parser.Node) parser.MetadataOpts {
+	if node.Source != nil && node.Source.Kind == parser.SourceFile {
+		return parser.MetadataOpts{TagName: parser.TagFile, AllowSliceAsStruct: false}
+	}
+
+	return parser.MetadataOpts{TagName: parser.TagLabel, AllowSliceAsStruct: true}
+}
+
+// withOrigins sets the origin of the decoding errors from the source of their values.
+func withOrigins(err error) error {
+	for _, dErr := range parser.AsDecodeErrors(err) {
+		if dErr.Origin == "" && dErr.Source != nil {
+			dErr.Origin = dErr.Source.String()
+		}
+	}
+
+	return err
+}
+
+func getPrecedence(node *parser.Node) int {
+	if node.Source == nil {
+		return 0
+	}
+
+	return precedences[node.Source.Kind]
+}
+
+func getSources(sources map[string]*parser.Source, node *parser.Node, path string) {
+	if len(node.Children) == 0 {
+		if node.Source != nil {
+			sources[path] = node.Source
+		}
+		return
+	}
+
+	for _, child := range node.Children {
+		childPath := path + "." + child.Name
+		if strings.HasPrefix(child.Name, "[") {
+			childPath = path + child.Name
+		}
+
+		getSources(sources, child, childPath)
+	}
+}
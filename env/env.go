@@ -2,8 +2,10 @@
 package env
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/traefik/paerser/parser"
@@ -18,22 +20,145 @@ const DefaultNamePrefix = "TRAEFIK_"
 // - map -> tree of untyped nodes
 // - untyped nodes -> nodes augmented with metadata such as kind (inferred from element)
 // - "typed" nodes -> typed element.
+// The variables with FileSuffix (ex: TRAEFIK_DB_PASSWORD_FILE) are replaced by the variables without the suffix,
+// with the content of their files as values, unless they match a field of the element (ex: TRAEFIK_PROVIDERS_FILE).
 func Decode(environ []string, prefix string, element interface{}) error {
+	return DecodeWithOpts(environ, prefix, element, parser.DecodeOpts{})
+}
+
+// DecodeWithOpts decodes the given environment variables into the given element, according to the options.
+func DecodeWithOpts(environ []string, prefix string, element interface{}, opts parser.DecodeOpts) error {
 	if err := checkPrefix(prefix); err != nil {
 		return err
 	}
 
+	environ, names, secrets, err := getVars(environ, prefix, element)
+	if err != nil {
+		return err
+	}
+
+	if onDeprecation := opts.OnDeprecation; onDeprecation != nil {
+		opts.OnDeprecation = func(d parser.Deprecation) {
+			d.Source = getVarName(names, d.OldPath)
+			onDeprecation(d)
+		}
+	}
+
+	node, err := decodeToNode(environ, prefix, names, secrets)
+	if err != nil {
+		return err
+	}
+
+	// the values of the environment variables are not interpolated.
+	opts.Interpolate = false
+
+	err = parser.DecodeLabelNode(element, node, opts)
+	for _, dErr := range parser.AsDecodeErrors(err) {
+		dErr.Origin = getVarName(names, dErr.Path)
+
+		if dErr.Suggestion != "" {
+			dErr.Suggestion = strings.ToUpper(strings.ReplaceAll(dErr.Suggestion, ".", "_"))
+		}
+	}
+
+	var unknownErr *parser.UnknownKeysError
+	if errors.As(err, &unknownErr) {
+		var keys []string
+		for _, key := range unknownErr.Keys {
+			keys = append(keys, getVarNames(names, key)...)
+		}
+
+		sort.Strings(keys)
+		unknownErr.Keys = keys
+	}
+
+	return err
+}
+
+// DecodeToNode decodes the given environment variables into a tree of untyped nodes, holding the source of their values.
+// The variables with FileSuffix which don't match a field of the given element are replaced by the content of their files.
+func DecodeToNode(environ []string, prefix string, element interface{}) (*parser.Node, error) {
+	if err := checkPrefix(prefix); err != nil {
+		return nil, err
+	}
+
+	environ, names, secrets, err := getVars(environ, prefix, element)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeToNode(environ, prefix, names, secrets)
+}
+
+func decodeToNode(environ []string, prefix string, names map[string]string, secrets map[string]bool) (*parser.Node, error) {
 	vars := make(map[string]string)
 	for _, evr := range environ {
 		k, v, _ := strings.Cut(evr, "=")
 		if strings.HasPrefix(strings.ToUpper(k), prefix) {
-			key := strings.ReplaceAll(strings.ToLower(k), "_", ".")
-			vars[key] = v
+			vars[strings.ReplaceAll(strings.ToLower(k), "_", ".")] = v
 		}
 	}
 
 	rootName := strings.ToLower(prefix[:len(prefix)-1])
-	return parser.Decode(vars, element, rootName)
+
+	node, err := parser.DecodeToNode(vars, rootName)
+	if err != nil {
+		return nil, err
+	}
+
+	parser.SetSources(node, func(path string) *parser.Source {
+		if name, ok := names[strings.ToLower(path)]; ok {
+			return &parser.Source{Kind: parser.SourceEnv, Name: name}
+		}
+		return nil
+	})
+
+	setSensitive(node, secrets)
+
+	return node, nil
+}
+
+// getNames returns the names of the prefixed environment variables, indexed by their key.
+func getNames(environ []string, prefix string) map[string]string {
+	names := make(map[string]string)
+	for _, evr := range environ {
+		k, _, _ := strings.Cut(evr, "=")
+		if strings.HasPrefix(strings.ToUpper(k), prefix) {
+			names[strings.ReplaceAll(strings.ToLower(k), "_", ".")] = k
+		}
+	}
+
+	return names
+}
+
+// getVarName returns the name of the environment variable related to the given path.
+// If the path is related to several variables (i.e. not a leaf), the first one is returned.
+func getVarName(names map[string]string, path string) string {
+	varNames := getVarNames(names, path)
+	if len(varNames) == 0 {
+		return ""
+	}
+
+	return varNames[0]
+}
+
+// getVarNames returns the sorted names of the environment variables related to the given path.
+func getVarNames(names map[string]string, path string) []string {
+	key := strings.ToLower(path)
+	if name, ok := names[key]; ok {
+		return []string{name}
+	}
+
+	var varNames []string
+	for k, name := range names {
+		if strings.HasPrefix(k, key+".") {
+			varNames = append(varNames, name)
+		}
+	}
+
+	sort.Strings(varNames)
+
+	return varNames
 }
 
 // Encode encodes the configuration in element into the environment variables represented in the returned Flats.
@@ -52,7 +177,7 @@ func Encode(prefix string, element interface{}) ([]parser.Flat, error) {
 		return nil, nil
 	}
 
-	etnOpts := parser.EncoderToNodeOpts{OmitEmpty: false, TagName: parser.TagLabel, AllowSliceAsStruct: true}
+	etnOpts := parser.EncoderToNodeOpts{OmitEmpty: false, TagName: parser.TagLabel, AllowSliceAsStruct: true, Redact: true}
 	node, err := parser.EncodeToNode(element, rootName, etnOpts)
 	if err != nil {
 		return nil, err
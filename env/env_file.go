@@ -0,0 +1,174 @@
+package env
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/traefik/paerser/parser"
+)
+
+// FileSuffix is the suffix of the environment variables holding the path of a file,
+// whose content is the value of the variable without the suffix (ex: TRAEFIK_DB_PASSWORD_FILE=/run/secrets/db_password).
+const FileSuffix = "_FILE"
+
+// MaxFileSize is the maximum size, in bytes, of the files read through the variables with FileSuffix.
+const MaxFileSize = 1 << 20
+
+// getVars returns the prefixed environment variables, the variables with FileSuffix replaced by the content of their files,
+// the names of the variables indexed by their key (the names of the variables with FileSuffix are kept),
+// and the keys of the variables read from files, whose values are sensitive.
+// The variables with FileSuffix matching a field of the element are not replaced (ex: TRAEFIK_PROVIDERS_FILE).
+func getVars(environ []string, prefix string, element interface{}) ([]string, map[string]string, map[string]bool, error) {
+	var rootType reflect.Type
+	if element != nil {
+		rootType = reflect.TypeOf(element)
+	}
+
+	values := make(map[string]string)
+	for _, evr := range environ {
+		k, v, _ := strings.Cut(evr, "=")
+		values[strings.ToUpper(k)] = v
+	}
+
+	vars := make([]string, 0, len(environ))
+	// names of the variables with FileSuffix, indexed by the names of the replaced variables.
+	fileVars := make(map[string]string)
+
+	for _, evr := range environ {
+		k, v, _ := strings.Cut(evr, "=")
+
+		upperKey := strings.ToUpper(k)
+		if !strings.HasPrefix(upperKey, prefix) || !strings.HasSuffix(strings.TrimPrefix(upperKey, prefix), FileSuffix) {
+			vars = append(vars, evr)
+			continue
+		}
+
+		path := strings.Split(strings.ToLower(strings.TrimPrefix(upperKey, prefix)), "_")
+		if rootType != nil && isFieldPath(rootType, path) {
+			vars = append(vars, evr)
+			continue
+		}
+
+		name := k[:len(k)-len(FileSuffix)]
+		if _, ok := values[strings.ToUpper(name)]; ok {
+			return nil, nil, nil, fmt.Errorf("both %s and %s are set", name, k)
+		}
+
+		content, err := readFile(v)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", k, err)
+		}
+
+		vars = append(vars, name+"="+content)
+		fileVars[name] = k
+	}
+
+	names := getNames(vars, prefix)
+	secrets := make(map[string]bool)
+	for key, name := range names {
+		if fileVar, ok := fileVars[name]; ok {
+			names[key] = fileVar
+			secrets[key] = true
+		}
+	}
+
+	return vars, names, secrets, nil
+}
+
+// setSensitive marks the nodes of the variables read from files as sensitive (see parser.Node).
+func setSensitive(root *parser.Node, secrets map[string]bool) {
+	for key := range secrets {
+		if node := root.Get(strings.TrimPrefix(key, root.Name+".")); node != nil {
+			node.Sensitive = true
+		}
+	}
+}
+
+// readFile returns the content of the file, without its trailing newline.
+func readFile(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	content, err := io.ReadAll(io.LimitReader(f, MaxFileSize+1))
+	if err != nil {
+		return "", err
+	}
+
+	if len(content) > MaxFileSize {
+		return "", fmt.Errorf("the file %s exceeds the maximum size of %d bytes", filePath, MaxFileSize)
+	}
+
+	value := strings.TrimSuffix(string(content), "\n")
+
+	return strings.TrimSuffix(value, "\r"), nil
+}
+
+// isFieldPath reports whether the path (the lower case segments of an environment variable name) matches a field of rType.
+func isFieldPath(rType reflect.Type, path []string) bool {
+	for rType.Kind() == reflect.Pointer {
+		rType = rType.Elem()
+	}
+
+	if len(path) == 0 {
+		return true
+	}
+
+	if parser.IsCustomType(rType) {
+		return false
+	}
+
+	switch rType.Kind() {
+	case reflect.Struct:
+		for i := 0; i < rType.NumField(); i++ {
+			field := rType.Field(i)
+
+			if !parser.IsExported(field) || field.Tag.Get(parser.TagLabel) == "-" {
+				continue
+			}
+
+			if field.Anonymous && isFieldPath(field.Type, path) {
+				return true
+			}
+
+			names := append([]string{field.Name}, parser.GetAliases(field)...)
+			for _, name := range names {
+				if strings.EqualFold(name, path[0]) && isFieldPath(field.Type, path[1:]) {
+					return true
+				}
+			}
+		}
+
+		return false
+
+	case reflect.Map:
+		return isFieldPath(rType.Elem(), path[1:])
+
+	case reflect.Slice:
+		elemType := rType.Elem()
+		for elemType.Kind() == reflect.Pointer {
+			elemType = elemType.Elem()
+		}
+
+		if elemType.Kind() != reflect.Struct || parser.IsCustomType(elemType) {
+			return false
+		}
+
+		// the elements are indexed (ex: SERVERS_0_URL), or a single element is used as a struct (ex: SERVERS_URL).
+		if _, err := strconv.Atoi(path[0]); err == nil && isFieldPath(elemType, path[1:]) {
+			return true
+		}
+
+		return isFieldPath(elemType, path)
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,156 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/parser"
+)
+
+type Secrets struct {
+	DB        *SecretsDB
+	Token     string
+	Providers *SecretsProviders
+}
+
+type SecretsDB struct {
+	User     string
+	Password string
+	Port     int
+}
+
+type SecretsProviders struct {
+	File *struct {
+		Filename string
+	} `label:"allowEmpty"`
+}
+
+func writeSecret(t *testing.T, content string) string {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), "secret")
+
+	err := os.WriteFile(filePath, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	return filePath
+}
+
+func TestDecode_fileSuffix(t *testing.T) {
+	passwordPath := writeSecret(t, "s3cr3t-env-password\n")
+	tokenPath := writeSecret(t, "s3cr3t-env-token")
+
+	environ := []string{
+		"TRAEFIK_DB_USER=user",
+		"TRAEFIK_DB_PASSWORD_FILE=" + passwordPath,
+		"TRAEFIK_TOKEN_FILE=" + tokenPath,
+		"TRAEFIK_PROVIDERS_FILE=true",
+		"TRAEFIK_PROVIDERS_FILE_FILENAME=/etc/traefik/dynamic.toml",
+	}
+
+	element := &Secrets{}
+	err := Decode(environ, DefaultNamePrefix, element)
+	require.NoError(t, err)
+
+	assert.Equal(t, &SecretsDB{User: "user", Password: "s3cr3t-env-password"}, element.DB)
+	assert.Equal(t, "s3cr3t-env-token", element.Token)
+	require.NotNil(t, element.Providers.File)
+	assert.Equal(t, "/etc/traefik/dynamic.toml", element.Providers.File.Filename)
+
+}
+
+func TestDecode_fileSuffix_notRedacted(t *testing.T) {
+	userPath := writeSecret(t, "admin")
+
+	environ := []string{
+		"TRAEFIK_DB_USER_FILE=" + userPath,
+		"TRAEFIK_TOKEN=admin-token",
+	}
+
+	element := &Secrets{}
+	err := Decode(environ, DefaultNamePrefix, element)
+	require.NoError(t, err)
+
+	// the content of the files doesn't alter the other values.
+	flats, err := Encode(DefaultNamePrefix, element)
+	require.NoError(t, err)
+
+	defaults := make(map[string]string)
+	for _, flat := range flats {
+		defaults[flat.Name] = flat.Default
+	}
+
+	assert.Equal(t, "admin", defaults["TRAEFIK_DB_USER"])
+	assert.Equal(t, "admin-token", defaults["TRAEFIK_TOKEN"])
+
+	err = Decode([]string{"TRAEFIK_DB_PORT=admin"}, DefaultNamePrefix, &Secrets{})
+	assert.EqualError(t, err, `traefik.db.port (TRAEFIK_DB_PORT): cannot decode "admin" as int: strconv.ParseInt: parsing "admin": invalid syntax`)
+}
+
+func TestDecode_fileSuffix_errors(t *testing.T) {
+	bigPath := writeSecret(t, strings.Repeat("a", MaxFileSize+1))
+	portPath := writeSecret(t, "s3cr3t-env-port")
+
+	testCases := []struct {
+		desc     string
+		environ  []string
+		expected string
+	}{
+		{
+			desc:     "both forms",
+			environ:  []string{"TRAEFIK_DB_PASSWORD=foo", "TRAEFIK_DB_PASSWORD_FILE=/run/secrets/password"},
+			expected: "both TRAEFIK_DB_PASSWORD and TRAEFIK_DB_PASSWORD_FILE are set",
+		},
+		{
+			desc:     "missing file",
+			environ:  []string{"TRAEFIK_DB_PASSWORD_FILE=/missing/password"},
+			expected: "TRAEFIK_DB_PASSWORD_FILE: open /missing/password: no such file or directory",
+		},
+		{
+			desc:     "file too large",
+			environ:  []string{"TRAEFIK_DB_PASSWORD_FILE=" + bigPath},
+			expected: "TRAEFIK_DB_PASSWORD_FILE: the file " + bigPath + " exceeds the maximum size of 1048576 bytes",
+		},
+		{
+			desc:     "invalid value",
+			environ:  []string{"TRAEFIK_DB_PORT_FILE=" + portPath},
+			expected: `traefik.db.port (TRAEFIK_DB_PORT_FILE): cannot decode "<redacted>" as int: strconv.ParseInt: parsing "<redacted>": invalid syntax`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := Decode(test.environ, DefaultNamePrefix, &Secrets{})
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestDecodeToNode_fileSuffix(t *testing.T) {
+	passwordPath := writeSecret(t, "s3cr3t-env-node")
+
+	node, err := DecodeToNode([]string{"TRAEFIK_DB_PASSWORD_FILE=" + passwordPath}, DefaultNamePrefix, &Secrets{})
+	require.NoError(t, err)
+
+	password := node.Get("db.password")
+	require.NotNil(t, password)
+
+	assert.Equal(t, "s3cr3t-env-node", password.Value)
+	assert.True(t, password.Sensitive)
+	assert.Equal(t, &parser.Source{Kind: parser.SourceEnv, Name: "TRAEFIK_DB_PASSWORD_FILE"}, password.Source)
+}
+
+func TestFindPrefixedEnvVars_fileSuffix(t *testing.T) {
+	environ := []string{"TRAEFIK_DB_PASSWORD_FILE=/run/secrets/password", "TRAEFIK_TOKEN_FILE=/run/secrets/token", "TRAEFIK_OTHER_FILE=foo"}
+
+	vars := FindPrefixedEnvVars(environ, DefaultNamePrefix, &Secrets{})
+
+	assert.Equal(t, []string{"TRAEFIK_DB_PASSWORD_FILE=/run/secrets/password", "TRAEFIK_TOKEN_FILE=/run/secrets/token"}, vars)
+}
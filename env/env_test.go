@@ -460,3 +460,144 @@ func TestEncode(t *testing.T) {
 
 	assert.Equal(t, expected, flats)
 }
+
+func TestDecode_decodeError(t *testing.T) {
+	environ := []string{"TRAEFIK_FOO=bar", "TRAEFIK_BAR_BAZ=qux"}
+
+	element := &struct {
+		Foo string
+		Bar struct {
+			Baz int
+		}
+	}{}
+
+	err := Decode(environ, DefaultNamePrefix, element)
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, "traefik.bar.baz", dErrs[0].Path)
+	assert.Equal(t, "TRAEFIK_BAR_BAZ", dErrs[0].Origin)
+}
+
+func TestDecodeWithOpts_aggregateErrors(t *testing.T) {
+	environ := []string{"TRAEFIK_FOO=bar", "TRAEFIK_BAR_BAZ=qux", "TRAEFIK_BAR_QUX=true", "TRAEFIK_FUU=42"}
+
+	element := &struct {
+		Foo int
+		Bar struct {
+			Baz int
+			Qux bool
+		}
+		Fuu int
+	}{}
+
+	err := DecodeWithOpts(environ, DefaultNamePrefix, element, parser.DecodeOpts{AggregateErrors: true})
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 2)
+
+	assert.Equal(t, "TRAEFIK_BAR_BAZ", dErrs[0].Origin)
+	assert.Equal(t, "TRAEFIK_FOO", dErrs[1].Origin)
+
+	assert.True(t, element.Bar.Qux)
+	assert.Equal(t, 42, element.Fuu)
+}
+
+func TestDecodeWithOpts_unknownKeys(t *testing.T) {
+	environ := []string{"TRAEFIK_FOO=bar", "TRAEFIK_BAR_BAZ=qux", "TRAEFIK_BAR_QUX=fuu", "TRAEFIK_FII=true"}
+
+	element := &struct {
+		Foo string
+		Fii bool
+	}{}
+
+	err := DecodeWithOpts(environ, DefaultNamePrefix, element, parser.DecodeOpts{UnknownKeys: parser.UnknownKeysWarn})
+
+	var unknownErr *parser.UnknownKeysError
+	require.ErrorAs(t, err, &unknownErr)
+
+	assert.Equal(t, []string{"TRAEFIK_BAR_BAZ", "TRAEFIK_BAR_QUX"}, unknownErr.Keys)
+	assert.Equal(t, "bar", element.Foo)
+	assert.True(t, element.Fii)
+}
+
+func TestDecode_suggestion(t *testing.T) {
+	element := &struct {
+		EntryPoints map[string]*struct {
+			Address string
+		}
+	}{}
+
+	err := Decode([]string{"TRAEFIK_ENTRYPOINTS_WEB_ADRESS=:80"}, DefaultNamePrefix, element)
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, "TRAEFIK_ENTRYPOINTS_WEB_ADDRESS", dErrs[0].Suggestion)
+}
+
+func TestDecodeWithOpts_aliases(t *testing.T) {
+	environ := []string{"TRAEFIK_OLDFOO=bar"}
+
+	element := &struct {
+		Foo string `alias:"oldFoo"`
+	}{}
+
+	var deprecations []parser.Deprecation
+	opts := parser.DecodeOpts{
+		OnDeprecation: func(d parser.Deprecation) {
+			deprecations = append(deprecations, d)
+		},
+	}
+
+	err := DecodeWithOpts(FindPrefixedEnvVars(environ, DefaultNamePrefix, element), DefaultNamePrefix, element, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bar", element.Foo)
+
+	expected := []parser.Deprecation{
+		{OldPath: "traefik.oldfoo", NewPath: "traefik.foo", Source: "TRAEFIK_OLDFOO"},
+	}
+	assert.Equal(t, expected, deprecations)
+}
+
+func TestDecode_source(t *testing.T) {
+	element := &struct {
+		Foo struct {
+			Bar int
+		}
+	}{}
+
+	err := Decode([]string{"TRAEFIK_FOO_BAR=baz"}, DefaultNamePrefix, element)
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, &parser.Source{Kind: parser.SourceEnv, Name: "TRAEFIK_FOO_BAR"}, dErrs[0].Source)
+}
+
+func TestEncode_sensitive(t *testing.T) {
+	element := &struct {
+		Password string            `description:"password" sensitive:"true" default:"admin"`
+		Headers  map[string]string `sensitive:"true"`
+		User     string
+	}{
+		Headers: map[string]string{"X-Token": "abc"},
+		User:    "root",
+	}
+
+	flats, err := Encode("TRAEFIK_", element)
+	require.NoError(t, err)
+
+	expected := []parser.Flat{
+		{Name: "TRAEFIK_HEADERS_X-TOKEN", Default: parser.RedactedValue},
+		{Name: "TRAEFIK_PASSWORD", Description: "password", Default: parser.RedactedValue},
+		{Name: "TRAEFIK_USER", Default: "root"},
+	}
+	assert.Equal(t, expected, flats)
+}
@@ -8,6 +8,7 @@ import (
 )
 
 // FindPrefixedEnvVars finds prefixed environment variables.
+// The variables with FileSuffix are found as well (ex: TRAEFIK_DB_PASSWORD_FILE).
 func FindPrefixedEnvVars(environ []string, prefix string, element interface{}) []string {
 	prefixes := getRootPrefixes(element, prefix)
 
@@ -58,6 +59,10 @@ func getPrefixes(prefix string, rootType reflect.Type) []string {
 		}
 
 		names = append(names, prefix+strings.ToUpper(field.Name))
+
+		for _, alias := range parser.GetAliases(field) {
+			names = append(names, prefix+strings.ToUpper(alias))
+		}
 	}
 
 	return names
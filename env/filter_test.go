@@ -72,6 +72,14 @@ func Test_getRootFieldNames(t *testing.T) {
 			element:  &Ye{},
 			expected: []string{"TRAEFIK_FOO", "TRAEFIK_FII", "TRAEFIK_FUU"},
 		},
+		{
+			desc: "aliases",
+			element: &struct {
+				Foo string `alias:"oldFoo, olderFoo"`
+				Fii string
+			}{},
+			expected: []string{"TRAEFIK_FOO", "TRAEFIK_OLDFOO", "TRAEFIK_OLDERFOO", "TRAEFIK_FII"},
+		},
 	}
 
 	for _, test := range testCases {
@@ -1,12 +1,10 @@
-// Package file implements decoding between configuration in a file and a typed Configuration.
+// Package file implements encoding and decoding between configuration in a file and a typed Configuration.
 package file
 
 import (
-	"fmt"
+	"strings"
 
-	"github.com/BurntSushi/toml"
 	"github.com/traefik/paerser/parser"
-	"gopkg.in/yaml.v3"
 )
 
 const defaultRawSliceSeparator = "║"
@@ -17,24 +15,63 @@ const defaultRawSliceSeparator = "║"
 // - untyped nodes -> nodes augmented with metadata such as kind (inferred from element)
 // - "typed" nodes -> typed element.
 func Decode(filePath string, element interface{}) error {
+	return DecodeWithOpts(filePath, element, parser.DecodeOpts{})
+}
+
+// DecodeWithOpts decodes the given configuration file into the given element, according to the options.
+// The file path can be a directory (ex: conf.d), and the files can include other files (see IncludeKey).
+// The origin of the decode errors is the position of the related key in the file (ex: config.yml:42:7).
+func DecodeWithOpts(filePath string, element interface{}, opts parser.DecodeOpts) error {
 	if element == nil {
 		return nil
 	}
 
-	filters := getRootFieldNames(element)
-
-	root, err := decodeFileToNode(filePath, filters...)
+	root, err := decodeToNode(filePath, !hasIncludeField(element), getFilters(element, opts)...)
 	if err != nil {
 		return err
 	}
 
-	metaOpts := parser.MetadataOpts{TagName: parser.TagFile, AllowSliceAsStruct: false}
-	err = parser.AddMetadata(element, root, metaOpts)
-	if err != nil {
-		return err
+	if onDeprecation := opts.OnDeprecation; onDeprecation != nil {
+		opts.OnDeprecation = func(d parser.Deprecation) {
+			d.Source = filePath
+			onDeprecation(d)
+		}
 	}
 
-	return parser.Fill(element, root, parser.FillerOpts{AllowSliceAsStruct: false, RawSliceSeparator: defaultRawSliceSeparator})
+	err = decodeNode(element, root, opts)
+	for _, dErr := range parser.AsDecodeErrors(err) {
+		dErr.Origin = filePath
+		if dErr.Source != nil && dErr.Source.Name != "" {
+			dErr.Origin = dErr.Source.String()
+		}
+	}
+
+	return err
+}
+
+// DecodeToNode decodes the given configuration file into a tree of untyped nodes, holding the source of their values.
+// The root keys that don't match a field of the given element are skipped.
+func DecodeToNode(filePath string, element interface{}) (*parser.Node, error) {
+	return decodeToNode(filePath, !hasIncludeField(element), getRootFieldNames(element)...)
+}
+
+func decodeToNode(filePath string, includes bool, filters ...string) (*parser.Node, error) {
+	loader := &includeLoader{includes: includes, filters: filters}
+
+	return loader.load(filePath)
+}
+
+// setSources sets the source of the nodes, with the positions of the keys in the file.
+func setSources(root *parser.Node, filePath string, pos positions) {
+	parser.SetSources(root, func(path string) *parser.Source {
+		source := &parser.Source{Kind: parser.SourceFile, Name: filePath}
+		if p, ok := pos.get(path); ok {
+			source.Line = p.line
+			source.Column = p.column
+		}
+
+		return source
+	})
 }
 
 // DecodeContent decodes the given configuration file content into the given element.
@@ -43,29 +80,18 @@ func Decode(filePath string, element interface{}) error {
 // - untyped nodes -> nodes augmented with metadata such as kind (inferred from element)
 // - "typed" nodes -> typed element.
 func DecodeContent(content, extension string, element interface{}) error {
-	data := make(map[string]interface{})
-
-	switch extension {
-	case ".toml":
-		_, err := toml.Decode(content, &data)
-		if err != nil {
-			return err
-		}
-
-	case ".yml", ".yaml", ".json":
-		var err error
-		err = yaml.Unmarshal([]byte(content), &data)
-		if err != nil {
-			return err
-		}
+	return DecodeContentWithOpts(content, extension, element, parser.DecodeOpts{})
+}
 
-	default:
-		return fmt.Errorf("unsupported file extension: %s", extension)
+// DecodeContentWithOpts decodes the given configuration file content into the given element, according to the options.
+// The includes (see IncludeKey) are not supported.
+func DecodeContentWithOpts(content, extension string, element interface{}, opts parser.DecodeOpts) error {
+	data, pos, err := decodeContent([]byte(content), extension)
+	if err != nil {
+		return err
 	}
 
-	filters := getRootFieldNames(element)
-
-	node, err := decodeRawToNode(data, filters...)
+	node, err := decodeRawToNode(data, getFilters(element, opts)...)
 	if err != nil {
 		return err
 	}
@@ -74,11 +100,49 @@ func DecodeContent(content, extension string, element interface{}) error {
 		return nil
 	}
 
-	metaOpts := parser.MetadataOpts{TagName: parser.TagFile, AllowSliceAsStruct: false}
-	err = parser.AddMetadata(element, node, metaOpts)
-	if err != nil {
-		return err
+	setSources(node, "", pos)
+
+	err = decodeNode(element, node, opts)
+	for _, dErr := range parser.AsDecodeErrors(err) {
+		if dErr.Source != nil && dErr.Source.Line > 0 {
+			dErr.Origin = dErr.Source.String()
+		}
+	}
+
+	return err
+}
+
+// getFilters returns the root keys to decode.
+// The unknown root keys are skipped, unless an unknown keys policy is defined.
+func getFilters(element interface{}, opts parser.DecodeOpts) []string {
+	if opts.UnknownKeys != "" {
+		return nil
+	}
+
+	return getRootFieldNames(element)
+}
+
+func decodeNode(element interface{}, node *parser.Node, opts parser.DecodeOpts) error {
+	metaOpts := parser.MetadataOpts{
+		TagName:            parser.TagFile,
+		AllowSliceAsStruct: false,
+		AggregateErrors:    opts.AggregateErrors,
+		UnknownKeys:        opts.UnknownKeys,
+		OnDeprecation:      opts.OnDeprecation,
+	}
+	fillerOpts := parser.FillerOpts{
+		AllowSliceAsStruct: false,
+		RawSliceSeparator:  defaultRawSliceSeparator,
+		AggregateErrors:    opts.AggregateErrors,
+		Interpolate:        opts.Interpolate,
+		LookupEnv:          opts.LookupEnv,
+		Resolve:            opts.Resolve,
+	}
+
+	err := parser.DecodeNode(element, node, metaOpts, fillerOpts)
+	for _, dErr := range parser.AsDecodeErrors(err) {
+		dErr.Suggestion = strings.TrimPrefix(dErr.Suggestion, node.Name+".")
 	}
 
-	return parser.Fill(element, node, parser.FillerOpts{AllowSliceAsStruct: false, RawSliceSeparator: defaultRawSliceSeparator})
+	return err
 }
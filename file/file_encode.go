@@ -0,0 +1,259 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/traefik/paerser/parser"
+)
+
+// EncodeOpts Options for the file encoder.
+type EncodeOpts struct {
+	// Redact replaces the values of the sensitive fields (see parser.TagSensitive) by parser.RedactedValue.
+	// The redacted content is meant to be displayed, it cannot be decoded back.
+	Redact bool
+}
+
+// Encode encodes the given element into the content of a configuration file,
+// in the format related to the given extension (ex: ".toml", ".yaml", ".json").
+func Encode(element interface{}, extension string) ([]byte, error) {
+	return EncodeWithOpts(element, extension, EncodeOpts{})
+}
+
+// EncodeWithOpts encodes the given element into the content of a configuration file,
+// in the format related to the given extension (ex: ".toml", ".yaml", ".json"), according to the options.
+func EncodeWithOpts(element interface{}, extension string, opts EncodeOpts) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	if err := EncodeToWithOpts(buf, extension, element, opts); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// EncodeTo encodes the given element into w, in the given format ("toml", "yaml", "yml", "json", or a registered format).
+// The operation goes through two stages roughly summarized as:
+// - typed element -> raw map, the names of the fields are converted to lower camel case
+// - raw map -> file contents.
+// The fields ignored by the file tag ("-") and the empty values are omitted,
+// and the pointers to empty structs are kept if allowed by the file tag ("allowEmpty").
+func EncodeTo(w io.Writer, format string, element interface{}) error {
+	return EncodeToWithOpts(w, format, element, EncodeOpts{})
+}
+
+// EncodeToWithOpts encodes the given element into w, in the given format, according to the options (see EncodeTo).
+func EncodeToWithOpts(w io.Writer, format string, element interface{}, opts EncodeOpts) error {
+	data := map[string]interface{}{}
+
+	if element != nil {
+		value, err := encoder{EncodeOpts: opts}.encodeValue(reflect.ValueOf(element))
+		if err != nil {
+			return err
+		}
+
+		if m, ok := value.(map[string]interface{}); ok {
+			data = m
+		}
+	}
+
+	f, ok := getFormat(format)
+	if !ok || f.encode == nil {
+		return fmt.Errorf("unsupported file extension: %s", format)
+	}
+
+	return f.encode(w, data)
+}
+
+type encoder struct {
+	EncodeOpts
+}
+
+// encodeValue converts a typed value to a raw value, nil if the value is omitted.
+func (e encoder) encodeValue(rValue reflect.Value) (interface{}, error) {
+	if !rValue.IsValid() {
+		return nil, nil
+	}
+
+	if parser.IsCustomType(rValue.Type()) {
+		value, ok, err := parser.EncodeCustomValue(rValue)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			if value == "" {
+				return nil, nil
+			}
+			return value, nil
+		}
+	}
+
+	switch rValue.Kind() {
+	case reflect.String:
+		return rValue.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rValue.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rValue.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rValue.Float(), nil
+	case reflect.Bool:
+		return rValue.Bool(), nil
+	case reflect.Pointer, reflect.Interface:
+		if rValue.IsNil() {
+			return nil, nil
+		}
+		return e.encodeValue(rValue.Elem())
+	case reflect.Struct:
+		data := map[string]interface{}{}
+		err := e.encodeStruct(data, rValue)
+		return data, err
+	case reflect.Map:
+		return e.encodeMap(rValue)
+	case reflect.Slice:
+		return e.encodeSlice(rValue)
+	default:
+		return nil, nil
+	}
+}
+
+func (e encoder) encodeStruct(data map[string]interface{}, rValue reflect.Value) error {
+	rType := rValue.Type()
+
+	for i := 0; i < rValue.NumField(); i++ {
+		field := rType.Field(i)
+		fieldValue := rValue.Field(i)
+
+		if !parser.IsExported(field) || field.Tag.Get(parser.TagFile) == "-" {
+			continue
+		}
+
+		if field.Anonymous {
+			if fieldValue.Kind() == reflect.Pointer {
+				if fieldValue.IsNil() {
+					continue
+				}
+				fieldValue = fieldValue.Elem()
+			}
+
+			if fieldValue.Kind() == reflect.Struct && !parser.IsCustomType(fieldValue.Type()) {
+				if err := e.encodeStruct(data, fieldValue); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		value, err := e.encodeValue(fieldValue)
+		if err != nil {
+			return err
+		}
+
+		if value == nil || isEmptyValue(field, value) {
+			continue
+		}
+
+		if e.Redact && parser.IsSensitive(field.Tag) {
+			value = redactValue(value)
+		}
+
+		data[parser.LowerCamelCase(field.Name)] = value
+	}
+
+	return nil
+}
+
+// isEmptyValue returns true if the raw value of the field is omitted:
+// the empty strings, slices, and maps, and the empty structs not allowed by the file tag.
+func isEmptyValue(field reflect.StructField, value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		if len(v) > 0 || field.Type.Kind() == reflect.Map {
+			return len(v) == 0
+		}
+
+		return field.Tag.Get(parser.TagFile) != parser.TagLabelAllowEmpty
+	default:
+		return false
+	}
+}
+
+// redactValue returns a copy of the raw value, the non-empty leaf values replaced by parser.RedactedValue.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+
+	case string:
+		if v == "" {
+			return v
+		}
+		return parser.RedactedValue
+
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elt := range v {
+			m[key] = redactValue(elt)
+		}
+		return m
+
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, elt := range v {
+			s[i] = redactValue(elt)
+		}
+		return s
+
+	default:
+		return parser.RedactedValue
+	}
+}
+
+func (e encoder) encodeMap(rValue reflect.Value) (interface{}, error) {
+	if rValue.IsNil() || rValue.Len() == 0 {
+		return nil, nil
+	}
+
+	data := map[string]interface{}{}
+
+	for _, key := range rValue.MapKeys() {
+		value, err := e.encodeValue(rValue.MapIndex(key))
+		if err != nil {
+			return nil, err
+		}
+
+		if value != nil {
+			data[fmt.Sprint(key.Interface())] = value
+		}
+	}
+
+	return data, nil
+}
+
+func (e encoder) encodeSlice(rValue reflect.Value) (interface{}, error) {
+	if rValue.IsNil() || rValue.Len() == 0 {
+		return nil, nil
+	}
+
+	var values []interface{}
+
+	for i := 0; i < rValue.Len(); i++ {
+		value, err := e.encodeValue(rValue.Index(i))
+		if err != nil {
+			return nil, err
+		}
+
+		if value != nil {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,302 @@
+package file
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/types"
+)
+
+type Encoded struct {
+	Name       string
+	Port       int
+	Ratio      float64
+	Enabled    bool
+	Timeout    types.Duration
+	Delay      time.Duration
+	Names      []string
+	Ports      []int
+	Servers    []EncodedServer
+	Routes     map[string]*EncodedServer
+	Raw        map[string]interface{}
+	HTTPClient *EncodedServer
+	Health     *EncodedHealth `file:"allowEmpty"`
+	Metrics    *EncodedHealth
+	Internal   string `file:"-"`
+	Empty      string
+}
+
+type EncodedServer struct {
+	URL    string
+	Weight int
+}
+
+type EncodedHealth struct {
+	Path string
+}
+
+func newEncoded() *Encoded {
+	return &Encoded{
+		Name:    "foo",
+		Port:    8080,
+		Ratio:   0.5,
+		Enabled: true,
+		Timeout: types.Duration(30 * time.Second),
+		Delay:   1500 * time.Millisecond,
+		Names:   []string{"a", "b, c"},
+		Ports:   []int{80, 443},
+		Servers: []EncodedServer{{URL: "http://a", Weight: 1}, {URL: "http://b"}},
+		Routes: map[string]*EncodedServer{
+			"foo": {URL: "http://foo"},
+		},
+		Raw: map[string]interface{}{
+			"foo": "bar",
+			"baz": map[string]interface{}{"values": []interface{}{"a", "b"}},
+		},
+		HTTPClient: &EncodedServer{URL: "http://client"},
+		Health:     &EncodedHealth{},
+		Metrics:    &EncodedHealth{},
+		Internal:   "secret",
+	}
+}
+
+func TestEncode(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		extension string
+		expected  string
+	}{
+		{
+			desc:      "TOML",
+			extension: ".toml",
+			expected: `delay = "1.5s"
+enabled = true
+name = "foo"
+names = ["a", "b, c"]
+port = 8080
+ports = [80, 443]
+ratio = 0.5
+timeout = "30"
+
+[health]
+
+[httpClient]
+  url = "http://client"
+  weight = 0
+
+[raw]
+  foo = "bar"
+  [raw.baz]
+    values = ["a", "b"]
+
+[routes]
+  [routes.foo]
+    url = "http://foo"
+    weight = 0
+
+[[servers]]
+  url = "http://a"
+  weight = 1
+
+[[servers]]
+  url = "http://b"
+  weight = 0
+`,
+		},
+		{
+			desc:      "YAML",
+			extension: ".yaml",
+			expected: `delay: 1.5s
+enabled: true
+health: {}
+httpClient:
+  url: http://client
+  weight: 0
+name: foo
+names:
+  - a
+  - b, c
+port: 8080
+ports:
+  - 80
+  - 443
+ratio: 0.5
+raw:
+  baz:
+    values:
+      - a
+      - b
+  foo: bar
+routes:
+  foo:
+    url: http://foo
+    weight: 0
+servers:
+  - url: http://a
+    weight: 1
+  - url: http://b
+    weight: 0
+timeout: "30"
+`,
+		},
+		{
+			desc:      "JSON",
+			extension: "json",
+			expected: `{
+  "delay": "1.5s",
+  "enabled": true,
+  "health": {},
+  "httpClient": {
+    "url": "http://client",
+    "weight": 0
+  },
+  "name": "foo",
+  "names": [
+    "a",
+    "b, c"
+  ],
+  "port": 8080,
+  "ports": [
+    80,
+    443
+  ],
+  "ratio": 0.5,
+  "raw": {
+    "baz": {
+      "values": [
+        "a",
+        "b"
+      ]
+    },
+    "foo": "bar"
+  },
+  "routes": {
+    "foo": {
+      "url": "http://foo",
+      "weight": 0
+    }
+  },
+  "servers": [
+    {
+      "url": "http://a",
+      "weight": 1
+    },
+    {
+      "url": "http://b",
+      "weight": 0
+    }
+  ],
+  "timeout": "30"
+}
+`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			content, err := Encode(newEncoded(), test.extension)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(content))
+		})
+	}
+}
+
+func TestEncode_roundTrip(t *testing.T) {
+	for _, extension := range []string{".toml", ".yaml", ".yml", ".json"} {
+		extension := extension
+		t.Run(extension, func(t *testing.T) {
+			t.Parallel()
+
+			content, err := Encode(newEncoded(), extension)
+			require.NoError(t, err)
+
+			element := &Encoded{}
+			err = DecodeContent(string(content), extension, element)
+			require.NoError(t, err)
+
+			expected := newEncoded()
+			expected.Internal = ""
+			expected.Metrics = nil
+
+			assert.Equal(t, expected, element)
+		})
+	}
+}
+
+func TestEncodeTo(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	err := EncodeTo(buf, "toml", &Yo{Foo: "bar", Yi: &Yi{}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo = \"bar\"\n\n[yi]\n", buf.String())
+}
+
+func TestEncode_unsupportedExtension(t *testing.T) {
+	_, err := Encode(&Yo{}, ".ini")
+	assert.EqualError(t, err, "unsupported file extension: .ini")
+}
+
+func TestEncode_sensitive(t *testing.T) {
+	type Auth struct {
+		User  string
+		Token string
+		Port  int
+	}
+
+	element := &struct {
+		Password string `sensitive:"true"`
+		Empty    string `sensitive:"true"`
+		Auth     *Auth  `sensitive:"true"`
+		Name     string
+	}{
+		Password: "admin",
+		Auth:     &Auth{User: "root", Token: "xyz", Port: 8080},
+		Name:     "foo",
+	}
+
+	content, err := EncodeWithOpts(element, ".toml", EncodeOpts{Redact: true})
+	require.NoError(t, err)
+
+	expected := `name = "foo"
+password = "<redacted>"
+
+[auth]
+  port = "<redacted>"
+  token = "<redacted>"
+  user = "<redacted>"
+`
+	assert.Equal(t, expected, string(content))
+
+	// the sensitive values are kept by default, the content can be decoded back.
+	content, err = Encode(element, ".toml")
+	require.NoError(t, err)
+
+	expected = `name = "foo"
+password = "admin"
+
+[auth]
+  port = 8080
+  token = "xyz"
+  user = "root"
+`
+	assert.Equal(t, expected, string(content))
+
+	decoded := &struct {
+		Password string `sensitive:"true"`
+		Empty    string `sensitive:"true"`
+		Auth     *Auth  `sensitive:"true"`
+		Name     string
+	}{}
+	err = DecodeContent(string(content), ".toml", decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, element, decoded)
+}
@@ -1,45 +1,62 @@
 package file
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
 
-	"github.com/BurntSushi/toml"
 	"github.com/traefik/paerser/parser"
-	"gopkg.in/yaml.v3"
 )
 
 // decodeFileToNode decodes the configuration in filePath in a tree of untyped nodes.
 // If filters is not empty, it skips any configuration element whose name is not among filters.
 func decodeFileToNode(filePath string, filters ...string) (*parser.Node, error) {
-	content, err := os.ReadFile(filepath.Clean(filePath))
+	f, err := decodeFile(filePath, true, filters...)
 	if err != nil {
 		return nil, err
 	}
 
-	data := make(map[string]interface{})
+	return f.node, nil
+}
 
-	switch strings.ToLower(filepath.Ext(filePath)) {
-	case ".toml":
-		err = toml.Unmarshal(content, &data)
-		if err != nil {
-			return nil, err
+// fragment is a decoded configuration file.
+type fragment struct {
+	node *parser.Node
+	// positions are the positions of the keys in the file.
+	positions positions
+	// includes are the paths of the files and directories included by the file (see IncludeKey).
+	includes []string
+}
+
+// decodeFile decodes the configuration in filePath in a tree of untyped nodes.
+// If includes is true, the include key (see IncludeKey) lists the included paths, instead of being a configuration element.
+// If filters is not empty, it skips any configuration element whose name is not among filters.
+func decodeFile(filePath string, includes bool, filters ...string) (*fragment, error) {
+	content, err := os.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		return nil, err
+	}
+
+	data, pos, err := decodeContent(content, strings.ToLower(filepath.Ext(filePath)))
+	if err != nil {
+		if errors.Is(err, errUnsupportedExtension) {
+			return nil, fmt.Errorf("%w: %s", errUnsupportedExtension, filePath)
 		}
+		return nil, err
+	}
 
-	case ".yml", ".yaml", ".json":
-		err = yaml.Unmarshal(content, data)
+	var included []string
+	if includes {
+		included, err = getIncludes(data)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%s: %w", filePath, err)
 		}
-
-	default:
-		return nil, fmt.Errorf("unsupported file extension: %s", filePath)
 	}
 
-	if len(data) == 0 {
+	if len(data) == 0 && len(included) == 0 {
 		return nil, fmt.Errorf("no configuration found in file: %s", filePath)
 	}
 
@@ -48,11 +65,11 @@ func decodeFileToNode(filePath string, filters ...string) (*parser.Node, error)
 		return nil, err
 	}
 
-	if len(node.Children) == 0 {
+	if len(node.Children) == 0 && len(included) == 0 {
 		return nil, fmt.Errorf("no valid configuration found in file: %s", filePath)
 	}
 
-	return node, nil
+	return &fragment{node: node, positions: pos, includes: included}, nil
 }
 
 func getRootFieldNames(element interface{}) []string {
@@ -90,6 +107,7 @@ func getFieldNames(rootType reflect.Type) []string {
 		}
 
 		names = append(names, field.Name)
+		names = append(names, parser.GetAliases(field)...)
 	}
 
 	return names
@@ -29,6 +29,14 @@ func Test_getRootFieldNames(t *testing.T) {
 			element:  &Ye{},
 			expected: []string{"Foo", "Fii", "Fuu"},
 		},
+		{
+			desc: "aliases",
+			element: &struct {
+				Foo string `alias:"oldFoo"`
+				Fii string
+			}{},
+			expected: []string{"Foo", "oldFoo", "Fii"},
+		},
 	}
 
 	for _, test := range testCases {
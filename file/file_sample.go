@@ -0,0 +1,394 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/traefik/paerser/generator"
+	"github.com/traefik/paerser/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// EncodeSample generates a sample configuration file from the given element,
+// in the format related to the given extension (".toml", ".yaml", or ".yml").
+func EncodeSample(element interface{}, extension string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	if err := EncodeSampleTo(buf, extension, element); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// EncodeSampleTo writes a sample configuration file generated from the given element into w,
+// in the given format ("toml", "yaml", or "yml").
+// The element (a pointer to an empty struct) is initialized with generator.Generate,
+// so every option is listed with its default value, and the map entries use the MapNamePlaceholder as key.
+// The raw maps (map[string]interface{}) are omitted, as they have no known options.
+// The default values of the sensitive options (see parser.TagSensitive) are replaced by parser.RedactedValue.
+// The description of each option is written as a comment above the key.
+func EncodeSampleTo(w io.Writer, format string, element interface{}) error {
+	if element == nil {
+		return nil
+	}
+
+	if err := generator.GenerateWithError(element); err != nil {
+		return err
+	}
+
+	entries, err := getSampleEntries(reflect.ValueOf(element))
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "toml":
+		return writeTOMLSample(w, entries)
+
+	case "yml", "yaml":
+		return writeYAMLSample(w, entries)
+
+	default:
+		return fmt.Errorf("unsupported sample file extension: %s", format)
+	}
+}
+
+// sampleEntry is an option of the sample configuration.
+type sampleEntry struct {
+	name        string
+	description string
+	// value is the raw value of a leaf option.
+	value interface{}
+	// table is true for the structs and the maps, their options are the children.
+	table bool
+	// array is true for the slices of structs, their elements (tables) are the children.
+	array    bool
+	children []*sampleEntry
+}
+
+func getSampleEntries(rValue reflect.Value) ([]*sampleEntry, error) {
+	for rValue.Kind() == reflect.Pointer {
+		if rValue.IsNil() {
+			return nil, nil
+		}
+		rValue = rValue.Elem()
+	}
+
+	if rValue.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported type: %s", rValue.Type())
+	}
+
+	var entries []*sampleEntry
+
+	for i := 0; i < rValue.NumField(); i++ {
+		field := rValue.Type().Field(i)
+		fieldValue := rValue.Field(i)
+
+		if !parser.IsExported(field) || field.Tag.Get(parser.TagFile) == "-" || field.Tag.Get(parser.TagDescription) == "-" {
+			continue
+		}
+
+		if field.Anonymous && !parser.IsCustomType(field.Type) &&
+			(field.Type.Kind() == reflect.Struct || field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct) {
+			children, err := getSampleEntries(fieldValue)
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, children...)
+			continue
+		}
+
+		entry, err := getSampleEntry(fieldValue)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry == nil {
+			continue
+		}
+
+		entry.name = parser.LowerCamelCase(field.Name)
+		entry.description = field.Tag.Get(parser.TagDescription)
+
+		if parser.IsSensitive(field.Tag) {
+			redactSampleEntry(entry)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// getSampleEntry returns the sample entry of the value, nil if the value is omitted.
+func getSampleEntry(rValue reflect.Value) (*sampleEntry, error) {
+	rType := rValue.Type()
+
+	if rType.Kind() == reflect.Pointer {
+		if rValue.IsNil() {
+			return getSampleEntry(reflect.Zero(rType.Elem()))
+		}
+		return getSampleEntry(rValue.Elem())
+	}
+
+	if parser.IsCustomType(rType) {
+		value, err := getSampleValue(rValue)
+		return &sampleEntry{value: value}, err
+	}
+
+	switch rType.Kind() {
+	case reflect.Struct:
+		children, err := getSampleEntries(rValue)
+		return &sampleEntry{table: true, children: children}, err
+
+	case reflect.Map:
+		if rType.Elem().Kind() == reflect.Interface {
+			// the raw maps have no known options, and an empty table is not a valid value.
+			return nil, nil
+		}
+
+		entry := &sampleEntry{table: true}
+
+		keys := rValue.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		for _, key := range keys {
+			child, err := getSampleEntry(rValue.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+
+			if child == nil {
+				continue
+			}
+
+			child.name = fmt.Sprint(key.Interface())
+			entry.children = append(entry.children, child)
+		}
+
+		return entry, nil
+
+	case reflect.Slice:
+		elemType := rType.Elem()
+		if elemType.Kind() == reflect.Pointer {
+			elemType = elemType.Elem()
+		}
+
+		if elemType.Kind() != reflect.Struct || parser.IsCustomType(elemType) {
+			value, err := getSampleValue(rValue)
+			return &sampleEntry{value: value}, err
+		}
+
+		entry := &sampleEntry{array: true}
+		for i := 0; i < rValue.Len(); i++ {
+			child, err := getSampleEntry(rValue.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			entry.children = append(entry.children, child)
+		}
+
+		return entry, nil
+
+	default:
+		value, err := getSampleValue(rValue)
+		return &sampleEntry{value: value}, err
+	}
+}
+
+// redactSampleEntry replaces the values of the entry and its children by parser.RedactedValue (see redactValue).
+func redactSampleEntry(entry *sampleEntry) {
+	entry.value = redactValue(entry.value)
+
+	for _, child := range entry.children {
+		redactSampleEntry(child)
+	}
+}
+
+// getSampleValue returns the raw value of a leaf option, the empty values included.
+func getSampleValue(rValue reflect.Value) (interface{}, error) {
+	value, err := encoder{}.encodeValue(rValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if value != nil {
+		return value, nil
+	}
+
+	if rValue.Kind() == reflect.Slice {
+		return []interface{}{}, nil
+	}
+
+	return "", nil
+}
+
+/*
+TOML section
+*/
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func writeTOMLSample(w io.Writer, entries []*sampleEntry) error {
+	buf := &bytes.Buffer{}
+
+	writeTOMLTable(buf, entries, nil)
+
+	_, err := w.Write(bytes.TrimLeft(buf.Bytes(), "\n"))
+	return err
+}
+
+func writeTOMLTable(buf *bytes.Buffer, entries []*sampleEntry, keys []string) {
+	indent := strings.Repeat("  ", len(keys))
+
+	// the keys of the table must be written before the sub-tables.
+	for _, entry := range entries {
+		if entry.table || entry.array {
+			continue
+		}
+
+		writeTOMLComment(buf, indent, entry.description)
+		buf.WriteString(indent + formatTOMLKey(entry.name) + " = " + formatTOMLValue(entry.value) + "\n")
+	}
+
+	for _, entry := range entries {
+		if !entry.table && !entry.array {
+			continue
+		}
+
+		path := append(append([]string{}, keys...), formatTOMLKey(entry.name))
+		header := strings.Join(path, ".")
+
+		if entry.table {
+			buf.WriteString("\n")
+			writeTOMLComment(buf, indent, entry.description)
+			buf.WriteString(indent + "[" + header + "]\n")
+			writeTOMLTable(buf, entry.children, path)
+			continue
+		}
+
+		for _, child := range entry.children {
+			buf.WriteString("\n")
+			writeTOMLComment(buf, indent, entry.description)
+			buf.WriteString(indent + "[[" + header + "]]\n")
+			writeTOMLTable(buf, child.children, path)
+		}
+	}
+}
+
+func writeTOMLComment(buf *bytes.Buffer, indent, description string) {
+	if description == "" {
+		return
+	}
+
+	for _, line := range strings.Split(description, "\n") {
+		buf.WriteString(strings.TrimRight(indent+"# "+line, " ") + "\n")
+	}
+}
+
+func formatTOMLKey(key string) string {
+	if bareKey.MatchString(key) {
+		return key
+	}
+
+	return strconv.Quote(key)
+}
+
+func formatTOMLValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		var values []string
+		for _, elt := range v {
+			values = append(values, formatTOMLValue(elt))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+/*
+YAML section
+*/
+
+func writeYAMLSample(w io.Writer, entries []*sampleEntry) error {
+	root, err := getYAMLMapping(entries)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err = encoder.Encode(root); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func getYAMLMapping(entries []*sampleEntry) (*yaml.Node, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	if len(entries) == 0 {
+		mapping.Style = yaml.FlowStyle
+	}
+
+	for _, entry := range entries {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: entry.name, HeadComment: entry.description}
+
+		value, err := getYAMLValue(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		mapping.Content = append(mapping.Content, key, value)
+	}
+
+	return mapping, nil
+}
+
+func getYAMLValue(entry *sampleEntry) (*yaml.Node, error) {
+	switch {
+	case entry.table:
+		return getYAMLMapping(entry.children)
+
+	case entry.array:
+		sequence := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, child := range entry.children {
+			elt, err := getYAMLMapping(child.children)
+			if err != nil {
+				return nil, err
+			}
+
+			sequence.Content = append(sequence.Content, elt)
+		}
+		return sequence, nil
+
+	default:
+		value := &yaml.Node{}
+		if err := value.Encode(entry.value); err != nil {
+			return nil, err
+		}
+
+		if value.Kind == yaml.SequenceNode && len(value.Content) == 0 {
+			value.Style = yaml.FlowStyle
+		}
+
+		return value, nil
+	}
+}
@@ -0,0 +1,174 @@
+package file
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/parser"
+	"github.com/traefik/paerser/types"
+)
+
+type Sample struct {
+	Name     string                   `description:"Name of the instance."`
+	Port     int                      `description:"Port to listen on." default:"8080"`
+	Debug    bool                     `description:"Enable the debug mode."`
+	Timeout  types.Duration           `description:"Timeout of the requests."`
+	Names    []string                 `description:"Names of the hosts."`
+	Log      *SampleLog               `description:"Log configuration."`
+	Servers  []SampleServer           `description:"Backend servers."`
+	Routes   map[string]*SampleServer `description:"Routes by name."`
+	Plugins  map[string]interface{}   `description:"Raw configuration of the plugins."`
+	Internal string                   `file:"-"`
+	Hidden   string                   `description:"-"`
+}
+
+func (s *Sample) SetDefaults() {
+	s.Name = "traefik"
+	s.Timeout = types.Duration(30 * time.Second)
+}
+
+type SampleLog struct {
+	Level string `description:"Log level.\nOne of: debug, info, error."`
+}
+
+func (s *SampleLog) SetDefaults() {
+	s.Level = "error"
+}
+
+type SampleServer struct {
+	URL    string `description:"URL of the server."`
+	Weight int    `description:"Weight of the server."`
+}
+
+func TestEncodeSample(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		extension string
+		expected  string
+	}{
+		{
+			desc:      "TOML",
+			extension: ".toml",
+			expected: `# Name of the instance.
+name = "traefik"
+# Port to listen on.
+port = 8080
+# Enable the debug mode.
+debug = false
+# Timeout of the requests.
+timeout = "30"
+# Names of the hosts.
+names = []
+
+# Log configuration.
+[log]
+  # Log level.
+  # One of: debug, info, error.
+  level = "error"
+
+# Backend servers.
+[[servers]]
+  # URL of the server.
+  url = ""
+  # Weight of the server.
+  weight = 0
+
+# Routes by name.
+[routes]
+
+  [routes."<name>"]
+    # URL of the server.
+    url = ""
+    # Weight of the server.
+    weight = 0
+`,
+		},
+		{
+			desc:      "YAML",
+			extension: ".yaml",
+			expected: `# Name of the instance.
+name: traefik
+# Port to listen on.
+port: 8080
+# Enable the debug mode.
+debug: false
+# Timeout of the requests.
+timeout: "30"
+# Names of the hosts.
+names: []
+# Log configuration.
+log:
+  # Log level.
+  # One of: debug, info, error.
+  level: error
+# Backend servers.
+servers:
+  - # URL of the server.
+    url: ""
+    # Weight of the server.
+    weight: 0
+# Routes by name.
+routes:
+  <name>:
+    # URL of the server.
+    url: ""
+    # Weight of the server.
+    weight: 0
+`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			content, err := EncodeSample(&Sample{}, test.extension)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(content))
+
+			// the sample is a valid configuration file.
+			element := &Sample{}
+			err = DecodeContent(string(content), test.extension, element)
+			require.NoError(t, err)
+
+			expected := &Sample{
+				Name:    "traefik",
+				Port:    8080,
+				Timeout: types.Duration(30 * time.Second),
+				Log:     &SampleLog{Level: "error"},
+				Servers: []SampleServer{{}},
+				Routes:  map[string]*SampleServer{parser.MapNamePlaceholder: {}},
+			}
+			assert.Equal(t, expected, element)
+		})
+	}
+}
+
+func TestEncodeSample_unsupportedExtension(t *testing.T) {
+	_, err := EncodeSample(&Sample{}, ".json")
+	assert.EqualError(t, err, "unsupported sample file extension: .json")
+}
+
+func TestEncodeSample_sensitive(t *testing.T) {
+	element := &struct {
+		Password string `description:"Password of the admin." sensitive:"true" default:"admin"`
+		Token    string `description:"Token of the API." sensitive:"true"`
+		User     string `description:"Name of the admin." default:"root"`
+	}{}
+
+	content, err := EncodeSample(element, ".toml")
+	require.NoError(t, err)
+
+	expected := `# Password of the admin.
+password = "<redacted>"
+# Token of the API.
+token = ""
+# Name of the admin.
+user = "root"
+`
+	assert.Equal(t, expected, string(content))
+}
@@ -1,11 +1,15 @@
 package file
 
 import (
+	"net/netip"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/parser"
 )
 
 func TestDecode_TOML(t *testing.T) {
@@ -404,3 +408,356 @@ func TestDecodeContent_JSON_rawValue(t *testing.T) {
 	}
 	assert.Equal(t, expected, element)
 }
+
+func TestDecodeContent_TOML_customTypes(t *testing.T) {
+	content := `
+since = 2021-09-07T10:00:00Z
+trustedIPs = ["10.0.0.0/8", "192.168.0.0/16"]
+[network]
+ip = "10.0.0.1"
+`
+
+	element := &struct {
+		Since      time.Time
+		TrustedIPs []netip.Prefix
+		Network    struct {
+			IP netip.Addr
+		}
+	}{}
+
+	err := DecodeContent(content, ".toml", element)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2021, time.September, 7, 10, 0, 0, 0, time.UTC), element.Since)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")}, element.TrustedIPs)
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), element.Network.IP)
+}
+
+func TestDecodeContent_YAML_customTypes(t *testing.T) {
+	content := `
+trustedIPs:
+  - 10.0.0.0/8
+  - 192.168.0.0/16
+network:
+  ip: 10.0.0.1
+`
+
+	element := &struct {
+		TrustedIPs []netip.Prefix
+		Network    *struct {
+			IP *netip.Addr
+		}
+	}{}
+
+	err := DecodeContent(content, ".yaml", element)
+	require.NoError(t, err)
+
+	ip := netip.MustParseAddr("10.0.0.1")
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")}, element.TrustedIPs)
+	assert.Equal(t, &ip, element.Network.IP)
+}
+
+func TestDecode_decodeError(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "traefik.toml")
+
+	err := os.WriteFile(filePath, []byte(`
+[yi]
+fii = "bir"
+fuu = "bur"
+`), 0o600)
+	require.NoError(t, err)
+
+	element := &struct {
+		Yi struct {
+			Fii string
+			Fuu int
+		}
+	}{}
+
+	err = Decode(filePath, element)
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, "traefik.yi.fuu", dErrs[0].Path)
+	assert.Equal(t, "bur", dErrs[0].Value)
+	assert.Equal(t, filePath+":4:1", dErrs[0].Origin)
+	assert.Equal(t, &parser.Source{Kind: parser.SourceFile, Name: filePath, Line: 4, Column: 1}, dErrs[0].Source)
+}
+
+func TestDecodeContentWithOpts_aggregateErrors(t *testing.T) {
+	content := `
+foo = "bar"
+fii = "yes"
+[yi]
+foo = "foo"
+fuu = "bur"
+`
+
+	element := &struct {
+		Foo string
+		Fii bool
+		Yi  struct {
+			Foo string
+			Fuu int
+		}
+	}{}
+
+	err := DecodeContentWithOpts(content, ".toml", element, parser.DecodeOpts{AggregateErrors: true})
+	require.Error(t, err)
+
+	var paths []string
+	for _, dErr := range parser.AsDecodeErrors(err) {
+		paths = append(paths, dErr.Path)
+	}
+
+	assert.Equal(t, []string{"traefik.fii", "traefik.yi.fuu"}, paths)
+	assert.Equal(t, "bar", element.Foo)
+	assert.Equal(t, "foo", element.Yi.Foo)
+}
+
+func TestDecodeContentWithOpts_unknownKeys(t *testing.T) {
+	content := `
+foo = "bar"
+unknown = "root"
+[yi]
+foo = "foo"
+fuu = "bur"
+`
+
+	type Yi struct {
+		Foo string
+	}
+
+	type Element struct {
+		Foo string
+		Yi  Yi
+	}
+
+	testCases := []struct {
+		desc         string
+		policy       parser.UnknownKeysPolicy
+		expectedKeys []string
+		expectedErr  bool
+	}{
+		{
+			desc:        "default",
+			expectedErr: true,
+		},
+		{
+			desc:        "strict",
+			policy:      parser.UnknownKeysStrict,
+			expectedErr: true,
+		},
+		{
+			desc:         "warn",
+			policy:       parser.UnknownKeysWarn,
+			expectedKeys: []string{"traefik.unknown", "traefik.yi.fuu"},
+		},
+		{
+			desc:   "ignore",
+			policy: parser.UnknownKeysIgnore,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			element := &Element{}
+			err := DecodeContentWithOpts(content, ".toml", element, parser.DecodeOpts{UnknownKeys: test.policy})
+
+			if test.expectedErr {
+				require.ErrorIs(t, err, parser.ErrFieldNotFound)
+				return
+			}
+
+			if test.expectedKeys != nil {
+				var unknownErr *parser.UnknownKeysError
+				require.ErrorAs(t, err, &unknownErr)
+				assert.Equal(t, test.expectedKeys, unknownErr.Keys)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, &Element{Foo: "bar", Yi: Yi{Foo: "foo"}}, element)
+		})
+	}
+}
+
+func TestDecodeContentWithOpts_unknownRootKeys(t *testing.T) {
+	content := `
+foo = "bar"
+unknown = "root"
+`
+
+	element := &struct {
+		Foo string
+	}{}
+
+	err := DecodeContent(content, ".toml", element)
+	require.NoError(t, err)
+
+	err = DecodeContentWithOpts(content, ".toml", element, parser.DecodeOpts{UnknownKeys: parser.UnknownKeysStrict})
+	require.ErrorIs(t, err, parser.ErrFieldNotFound)
+}
+
+func TestDecodeContent_suggestion(t *testing.T) {
+	content := `
+[entryPoints.web]
+adress = ":80"
+`
+
+	element := &struct {
+		EntryPoints map[string]*struct {
+			Address string
+		}
+	}{}
+
+	err := DecodeContent(content, ".toml", element)
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, "entryPoints.web.address", dErrs[0].Suggestion)
+}
+
+func TestDecodeWithOpts_aliases(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "traefik.yml")
+
+	err := os.WriteFile(filePath, []byte(`
+oldFoo: bar
+yi:
+  oldFii: bir
+`), 0o600)
+	require.NoError(t, err)
+
+	element := &struct {
+		Foo string `alias:"oldFoo"`
+		Yi  struct {
+			Fii string `alias:"oldFii"`
+		}
+	}{}
+
+	var deprecations []parser.Deprecation
+	opts := parser.DecodeOpts{
+		OnDeprecation: func(d parser.Deprecation) {
+			deprecations = append(deprecations, d)
+		},
+	}
+
+	err = DecodeWithOpts(filePath, element, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bar", element.Foo)
+	assert.Equal(t, "bir", element.Yi.Fii)
+
+	expected := []parser.Deprecation{
+		{OldPath: "traefik.oldFoo", NewPath: "traefik.foo", Source: filePath},
+		{OldPath: "traefik.yi.oldFii", NewPath: "traefik.yi.fii", Source: filePath},
+	}
+	assert.Equal(t, expected, deprecations)
+}
+
+func TestDecodeContent_validation(t *testing.T) {
+	content := `
+[entryPoints.web]
+address = ":80"
+
+[entryPoints.websecure]
+address = "443"
+`
+
+	element := &struct {
+		EntryPoints map[string]struct {
+			Address string `paerser-validate:"required,pattern=^:[0-9]+$"`
+		}
+	}{}
+
+	err := DecodeContent(content, ".toml", element)
+	require.Error(t, err)
+
+	var vErr *parser.ValidationError
+	require.ErrorAs(t, err, &vErr)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+	assert.Equal(t, "traefik.entryPoints.websecure.address", dErrs[0].Path)
+}
+
+func TestDecodeWithOpts_interpolate(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "traefik.yml")
+
+	err := os.WriteFile(filePath, []byte(`foo: ${FOO}
+fii: ${FII:-fii}
+yi:
+  foo: $${FOO}
+  fuu: ${FUU}
+`), 0o600)
+	require.NoError(t, err)
+
+	env := map[string]string{"FOO": "foo"}
+	opts := parser.DecodeOpts{
+		Interpolate: true,
+		LookupEnv: func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		},
+	}
+
+	element := &Yo{}
+	err = DecodeWithOpts(filePath, element, opts)
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, "traefik.yi.fuu", dErrs[0].Path)
+	assert.Equal(t, filePath+":5:3", dErrs[0].Origin)
+	assert.ErrorIs(t, err, parser.ErrMissingVariable)
+
+	env["FUU"] = "fuu"
+
+	element = &Yo{}
+	err = DecodeWithOpts(filePath, element, opts)
+	require.NoError(t, err)
+
+	expected := &Yo{
+		Foo: "foo",
+		Fii: "fii",
+		Yi:  &Yi{Foo: "${FOO}", Fii: "fii", Fuu: "fuu"},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecodeContentWithOpts_resolvedSecretError(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(secretPath, []byte("s3cr3t\n"), 0o600)
+	require.NoError(t, err)
+
+	content := `
+foo = "${file:` + secretPath + `}"
+fuu = "80x"
+`
+
+	element := &struct {
+		Foo int
+		Fuu int
+	}{}
+
+	err = DecodeContentWithOpts(content, ".toml", element, parser.DecodeOpts{Resolve: true, AggregateErrors: true})
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 2)
+
+	assert.Equal(t, "traefik.foo", dErrs[0].Path)
+	assert.Equal(t, parser.RedactedValue, dErrs[0].Value)
+	assert.NotContains(t, err.Error(), "s3cr3t")
+
+	assert.Equal(t, "traefik.fuu", dErrs[1].Path)
+	assert.Equal(t, "80x", dErrs[1].Value)
+}
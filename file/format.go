@@ -0,0 +1,155 @@
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeFunc decodes the content of a configuration file into a raw map.
+type DecodeFunc func(content []byte) (map[string]interface{}, error)
+
+// EncodeFunc encodes a raw map into the content of a configuration file.
+type EncodeFunc func(w io.Writer, data map[string]interface{}) error
+
+var errUnsupportedExtension = errors.New("unsupported file extension")
+
+type format struct {
+	decode DecodeFunc
+	encode EncodeFunc
+	// positions returns the positions of the keys of a content, nil if unknown.
+	positions func(content []byte) positions
+}
+
+var formats = struct {
+	sync.RWMutex
+	byExt map[string]format
+	// exts holds the extensions in the registration order.
+	exts []string
+}{byExt: map[string]format{}}
+
+func init() {
+	registerFormat("toml", format{decode: decodeTOML, encode: encodeTOML, positions: getTOMLPositions})
+	registerFormat("yaml", format{decode: decodeYAML, encode: encodeYAML, positions: getYAMLPositions})
+	registerFormat("yml", format{decode: decodeYAML, encode: encodeYAML, positions: getYAMLPositions})
+	registerFormat("json", format{decode: decodeJSON, encode: encodeJSON, positions: getYAMLPositions})
+}
+
+// RegisterFormat registers a file format, identified by its extension (ex: "hcl" or ".hcl", case-insensitive).
+// The format is used to decode the configuration files (Decode, DecodeContent, ...) and to encode them (Encode, EncodeTo),
+// and its extension is added to the default extensions of the configuration file lookup (see Extensions).
+// The decode or encode function can be nil if the format is only decoded or only encoded.
+// Registering an already registered extension replaces its format.
+func RegisterFormat(ext string, decode DecodeFunc, encode EncodeFunc) {
+	registerFormat(ext, format{decode: decode, encode: encode})
+}
+
+func registerFormat(ext string, f format) {
+	ext = normalizeExtension(ext)
+	if ext == "" {
+		panic("file: RegisterFormat with an empty extension")
+	}
+
+	formats.Lock()
+	defer formats.Unlock()
+
+	if _, ok := formats.byExt[ext]; !ok {
+		formats.exts = append(formats.exts, ext)
+	}
+
+	formats.byExt[ext] = f
+}
+
+// Extensions returns the extensions (without dot) of the registered formats that can be decoded, in the registration order.
+func Extensions() []string {
+	formats.RLock()
+	defer formats.RUnlock()
+
+	var exts []string
+	for _, ext := range formats.exts {
+		if formats.byExt[ext].decode != nil {
+			exts = append(exts, ext)
+		}
+	}
+
+	return exts
+}
+
+func getFormat(ext string) (format, bool) {
+	formats.RLock()
+	defer formats.RUnlock()
+
+	f, ok := formats.byExt[normalizeExtension(ext)]
+	return f, ok
+}
+
+func normalizeExtension(ext string) string {
+	return strings.ToLower(strings.TrimPrefix(ext, "."))
+}
+
+// decodeContent decodes the content of a configuration file into a raw map,
+// and returns the positions of the keys in the content, if known.
+func decodeContent(content []byte, extension string) (map[string]interface{}, positions, error) {
+	f, ok := getFormat(extension)
+	if !ok || f.decode == nil {
+		return nil, nil, fmt.Errorf("%w: %s", errUnsupportedExtension, extension)
+	}
+
+	data, err := f.decode(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pos positions
+	if f.positions != nil {
+		pos = f.positions(content)
+	}
+
+	return data, pos, nil
+}
+
+func decodeTOML(content []byte) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	if err := toml.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func decodeYAML(content []byte) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	if err := yaml.Unmarshal(content, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func encodeTOML(w io.Writer, data map[string]interface{}) error {
+	return toml.NewEncoder(w).Encode(data)
+}
+
+func encodeYAML(w io.Writer, data map[string]interface{}) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(data); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func encodeJSON(w io.Writer, data map[string]interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(data)
+}
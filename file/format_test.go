@@ -0,0 +1,127 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeProperties decodes a flat "key=value" content, the dotted keys are nested maps.
+func decodeProperties(content []byte) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line: %s", line)
+		}
+
+		parts := strings.Split(strings.TrimSpace(key), ".")
+
+		current := data
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := current[part].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				current[part] = child
+			}
+			current = child
+		}
+
+		current[parts[len(parts)-1]] = strings.TrimSpace(value)
+	}
+
+	return data, scanner.Err()
+}
+
+// encodeProperties encodes the first level of a raw map as "key=value" lines.
+func encodeProperties(w io.Writer, data map[string]interface{}) error {
+	var lines []string
+	for key, value := range data {
+		if _, ok := value.(map[string]interface{}); ok {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s=%v\n", key, value))
+	}
+
+	sort.Strings(lines)
+
+	_, err := io.WriteString(w, strings.Join(lines, ""))
+	return err
+}
+
+func TestRegisterFormat(t *testing.T) {
+	RegisterFormat(".Properties", decodeProperties, encodeProperties)
+
+	assert.Contains(t, Extensions(), "properties")
+
+	filePath := filepath.Join(t.TempDir(), "config.properties")
+	err := os.WriteFile(filePath, []byte("foo = bar\nyi.fii = bir\n"), 0o600)
+	require.NoError(t, err)
+
+	element := &Yo{}
+	err = Decode(filePath, element)
+	require.NoError(t, err)
+
+	assert.Equal(t, &Yo{Foo: "bar", Yi: &Yi{Foo: "foo", Fii: "bir"}}, element)
+
+	element = &Yo{}
+	err = DecodeContent("foo = bar", "properties", element)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bar", element.Foo)
+
+	content, err := Encode(&Yo{Foo: "bar", Fii: "fii"}, ".properties")
+	require.NoError(t, err)
+
+	assert.Equal(t, "fii=fii\nfoo=bar\n", string(content))
+}
+
+func TestRegisterFormat_decodeOnly(t *testing.T) {
+	RegisterFormat("props", decodeProperties, nil)
+
+	assert.Contains(t, Extensions(), "props")
+
+	element := &Yo{}
+	err := DecodeContent("foo = bar", ".props", element)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bar", element.Foo)
+
+	_, err = Encode(element, ".props")
+	assert.EqualError(t, err, "unsupported file extension: .props")
+}
+
+func TestRegisterFormat_encodeOnly(t *testing.T) {
+	RegisterFormat("envfile", nil, encodeProperties)
+
+	assert.NotContains(t, Extensions(), "envfile")
+
+	err := DecodeContent("foo = bar", ".envfile", &Yo{})
+	assert.EqualError(t, err, "unsupported file extension: .envfile")
+}
+
+func TestRegisterFormat_emptyExtension(t *testing.T) {
+	assert.Panics(t, func() {
+		RegisterFormat(".", decodeProperties, encodeProperties)
+	})
+}
+
+func TestExtensions(t *testing.T) {
+	assert.Equal(t, []string{"toml", "yaml", "yml", "json"}, Extensions()[:4])
+}
@@ -0,0 +1,195 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/traefik/paerser/parser"
+)
+
+// IncludeKey is the reserved root key of the configuration files listing the files and directories to include:
+// a path, or a list of paths, relative to the directory of the including file.
+// The included configurations override the configuration of the including file, in the listed order.
+// A directory (ex: conf.d) includes its configuration files (see Extensions) in the lexical order,
+// the subdirectories and the hidden files are skipped.
+// The includes are disabled if the decoded element has a root field named as the key (ex: Include),
+// the key is then decoded as the value of this field.
+const IncludeKey = "include"
+
+// hasIncludeField reports whether the element has a root field (or alias) named as IncludeKey.
+func hasIncludeField(element interface{}) bool {
+	for _, name := range getRootFieldNames(element) {
+		if strings.EqualFold(name, IncludeKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getIncludes removes the include key from the root of the raw data, and returns the included paths.
+func getIncludes(data map[string]interface{}) ([]string, error) {
+	for key, value := range data {
+		if !strings.EqualFold(key, IncludeKey) {
+			continue
+		}
+
+		delete(data, key)
+
+		switch v := value.(type) {
+		case nil:
+			return nil, nil
+
+		case string:
+			return []string{v}, nil
+
+		case []interface{}:
+			var includes []string
+			for _, item := range v {
+				include, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid %s value: a path or a list of paths is expected", key)
+				}
+
+				includes = append(includes, include)
+			}
+
+			return includes, nil
+
+		default:
+			return nil, fmt.Errorf("invalid %s value: a path or a list of paths is expected", key)
+		}
+	}
+
+	return nil, nil
+}
+
+// includeLoader loads the configuration of a file or a directory, the included configurations merged.
+type includeLoader struct {
+	// includes enables the include key (see IncludeKey).
+	includes bool
+	filters  []string
+	// stack holds the absolute paths of the files being loaded, to detect the include cycles.
+	stack []string
+}
+
+// load loads the configuration located at path (a file or a directory) in a tree of untyped nodes,
+// the source of each node is the file (and the position) where its value is defined.
+func (l *includeLoader) load(path string) (*parser.Node, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return l.loadDirectory(path)
+	}
+
+	return l.loadFile(path)
+}
+
+func (l *includeLoader) loadDirectory(dirPath string) (*parser.Node, error) {
+	filePaths, err := getDirectoryFiles(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filePaths) == 0 {
+		return nil, fmt.Errorf("no configuration file found in directory: %s", dirPath)
+	}
+
+	var root *parser.Node
+	for _, filePath := range filePaths {
+		node, err := l.loadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		root, err = mergeFragments(root, node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(root.Children) == 0 {
+		return nil, fmt.Errorf("no valid configuration found in directory: %s", dirPath)
+	}
+
+	return root, nil
+}
+
+func (l *includeLoader) loadFile(filePath string) (*parser.Node, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, p := range l.stack {
+		if p == absPath {
+			cycle := append(append([]string{}, l.stack[i:]...), absPath)
+			return nil, fmt.Errorf("include cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	l.stack = append(l.stack, absPath)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	f, err := decodeFile(filePath, l.includes, l.filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	setSources(f.node, filePath, f.positions)
+
+	root := f.node
+	for _, include := range f.includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(filePath), include)
+		}
+
+		node, err := l.load(include)
+		if err != nil {
+			return nil, err
+		}
+
+		root, err = mergeFragments(root, node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(root.Children) == 0 {
+		return nil, fmt.Errorf("no valid configuration found in file: %s", filePath)
+	}
+
+	return root, nil
+}
+
+// getDirectoryFiles returns the configuration files of the directory, in the lexical order.
+func getDirectoryFiles(dirPath string) ([]string, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var filePaths []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		if f, ok := getFormat(filepath.Ext(entry.Name())); !ok || f.decode == nil {
+			continue
+		}
+
+		filePaths = append(filePaths, filepath.Join(dirPath, entry.Name()))
+	}
+
+	return filePaths, nil
+}
+
+func mergeFragments(base, override *parser.Node) (*parser.Node, error) {
+	return parser.MergeNodes(base, override, parser.MergeOpts{})
+}
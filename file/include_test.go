@@ -0,0 +1,252 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/parser"
+)
+
+type Included struct {
+	Name    string
+	Port    int
+	Names   []string
+	Servers map[string]*IncludedServer
+}
+
+type IncludedServer struct {
+	URL    string
+	Weight int
+}
+
+// writeFiles writes the files (path relative to dir -> content) in dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		filePath := filepath.Join(dir, name)
+
+		err := os.MkdirAll(filepath.Dir(filePath), 0o700)
+		require.NoError(t, err)
+
+		err = os.WriteFile(filePath, []byte(content), 0o600)
+		require.NoError(t, err)
+	}
+}
+
+func TestDecode_include(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"traefik.toml": `
+include = ["extra.yml", "conf.d"]
+name = "main"
+port = 80
+
+[servers.a]
+  url = "http://main-a"
+  weight = 1
+`,
+		"extra.yml": `
+names:
+  - foo
+  - bar
+`,
+		"conf.d/10-servers.toml": `
+[servers.a]
+  url = "http://a"
+
+[servers.b]
+  url = "http://b"
+`,
+		"conf.d/20-name.yaml": `
+name: conf.d
+`,
+		"conf.d/README.md":    "not a configuration file",
+		"conf.d/.hidden.toml": `name = "hidden"`,
+		"conf.d/sub/30.toml":  `name = "sub"`,
+	})
+
+	element := &Included{}
+	err := Decode(filepath.Join(dir, "traefik.toml"), element)
+	require.NoError(t, err)
+
+	expected := &Included{
+		Name:  "conf.d",
+		Port:  80,
+		Names: []string{"foo", "bar"},
+		Servers: map[string]*IncludedServer{
+			"a": {URL: "http://a", Weight: 1},
+			"b": {URL: "http://b"},
+		},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecode_directory(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"b.toml": `name = "b"`,
+		"a.toml": `
+name = "a"
+port = 80
+`,
+	})
+
+	element := &Included{}
+	err := Decode(dir, element)
+	require.NoError(t, err)
+
+	assert.Equal(t, &Included{Name: "b", Port: 80}, element)
+}
+
+func TestDecodeToNode_include_sources(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"traefik.toml": `
+include = "conf.d"
+name = "main"
+`,
+		"conf.d/servers.yml": `
+servers:
+  a:
+    url: http://a
+`,
+	})
+
+	node, err := DecodeToNode(filepath.Join(dir, "traefik.toml"), &Included{})
+	require.NoError(t, err)
+
+	assert.Equal(t, &parser.Source{Kind: parser.SourceFile, Name: filepath.Join(dir, "traefik.toml"), Line: 3, Column: 1}, node.Get("name").Source)
+	assert.Equal(t, &parser.Source{Kind: parser.SourceFile, Name: filepath.Join(dir, "conf.d", "servers.yml"), Line: 4, Column: 5}, node.Get("servers.a.url").Source)
+}
+
+func TestDecode_include_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			desc: "cycle",
+			files: map[string]string{
+				"traefik.toml": `include = "a.toml"`,
+				"a.toml":       `include = "b/b.toml"`,
+				"b/b.toml":     `include = "../a.toml"`,
+			},
+			expected: "include cycle: {dir}/a.toml -> {dir}/b/b.toml -> {dir}/a.toml",
+		},
+		{
+			desc: "self include",
+			files: map[string]string{
+				"traefik.toml": `include = "."`,
+			},
+			expected: "include cycle: {dir}/traefik.toml -> {dir}/traefik.toml",
+		},
+		{
+			desc: "invalid include value",
+			files: map[string]string{
+				"traefik.toml": `include = 42`,
+			},
+			expected: "{dir}/traefik.toml: invalid include value: a path or a list of paths is expected",
+		},
+		{
+			desc: "missing include",
+			files: map[string]string{
+				"traefik.toml": `include = "missing.toml"`,
+			},
+			expected: "stat {dir}/missing.toml: no such file or directory",
+		},
+		{
+			desc: "empty directory",
+			files: map[string]string{
+				"traefik.toml":     `include = "conf.d"`,
+				"conf.d/README.md": "",
+			},
+			expected: "no configuration file found in directory: {dir}/conf.d",
+		},
+		{
+			desc: "only an include",
+			files: map[string]string{
+				"traefik.toml": `include = "unknown.toml"`,
+				"unknown.toml": `unknown = "foo"`,
+			},
+			expected: "no valid configuration found in file: {dir}/unknown.toml",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			writeFiles(t, dir, test.files)
+
+			err := Decode(filepath.Join(dir, "traefik.toml"), &Included{})
+			require.Error(t, err)
+
+			assert.Equal(t, strings.ReplaceAll(test.expected, "{dir}", dir), err.Error())
+		})
+	}
+}
+
+func TestDecode_include_errorPosition(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"traefik.toml": `
+include = "conf.d"
+name = "main"
+`,
+		"conf.d/10-port.yml": `
+name: fragment
+port: foo
+`,
+	})
+
+	err := Decode(filepath.Join(dir, "traefik.toml"), &Included{})
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, filepath.Join(dir, "conf.d", "10-port.yml")+":3:1", dErrs[0].Origin)
+}
+
+func TestDecode_includeField(t *testing.T) {
+	type Config struct {
+		Name    string
+		Include []string
+	}
+
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"traefik.toml": `
+include = ["foo.toml", "bar.toml"]
+name = "main"
+`,
+	})
+
+	element := &Config{}
+	err := Decode(filepath.Join(dir, "traefik.toml"), element)
+	require.NoError(t, err)
+
+	expected := &Config{
+		Name:    "main",
+		Include: []string{"foo.toml", "bar.toml"},
+	}
+	assert.Equal(t, expected, element)
+
+	node, err := DecodeToNode(filepath.Join(dir, "traefik.toml"), element)
+	require.NoError(t, err)
+
+	assert.NotNil(t, node.Get("include"))
+}
@@ -0,0 +1,200 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// decodeJSON decodes a JSON content into a raw map.
+// The numbers are decoded as int, uint64, or float64 (the same shape as the YAML decoder),
+// the integers out of the range of int and uint64 are kept as strings to stay exact.
+// The duplicate keys are rejected, and the errors report their position in the content.
+func decodeJSON(content []byte) (map[string]interface{}, error) {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
+	d := &jsonDecoder{content: content, decoder: json.NewDecoder(bytes.NewReader(content))}
+	d.decoder.UseNumber()
+
+	start := d.offset()
+
+	value, err := d.decodeValue("")
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, d.errorf(start, "the root element must be an object")
+	}
+
+	offset := d.offset()
+	if _, err = d.decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, d.errorf(offset, "invalid content after the root element")
+	}
+
+	return data, nil
+}
+
+type jsonDecoder struct {
+	content []byte
+	decoder *json.Decoder
+}
+
+func (d *jsonDecoder) decodeValue(path string) (interface{}, error) {
+	offset := d.offset()
+
+	token, err := d.decoder.Token()
+	if err != nil {
+		return nil, d.wrapError(offset, err)
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			return d.decodeObject(path)
+		case '[':
+			return d.decodeArray(path)
+		default:
+			return nil, d.errorf(offset, "unexpected delimiter %q", t)
+		}
+
+	case json.Number:
+		return getJSONNumber(t), nil
+
+	default:
+		// string, bool, or nil.
+		return t, nil
+	}
+}
+
+func (d *jsonDecoder) decodeObject(path string) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+
+	for d.decoder.More() {
+		offset := d.offset()
+
+		token, err := d.decoder.Token()
+		if err != nil {
+			return nil, d.wrapError(offset, err)
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return nil, d.errorf(offset, "object key must be a string")
+		}
+
+		keyPath := joinKey(path, key)
+		if _, exists := data[key]; exists {
+			return nil, d.errorf(offset, "duplicate key %q", keyPath)
+		}
+
+		data[key], err = d.decodeValue(keyPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// closing delimiter.
+	if _, err := d.decoder.Token(); err != nil {
+		return nil, d.wrapError(d.offset(), err)
+	}
+
+	return data, nil
+}
+
+func (d *jsonDecoder) decodeArray(path string) ([]interface{}, error) {
+	values := []interface{}{}
+
+	for i := 0; d.decoder.More(); i++ {
+		value, err := d.decodeValue(path + "[" + strconv.Itoa(i) + "]")
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	// closing delimiter.
+	if _, err := d.decoder.Token(); err != nil {
+		return nil, d.wrapError(d.offset(), err)
+	}
+
+	return values, nil
+}
+
+// offset returns the offset of the next token, the separators (whitespaces, commas, and colons) skipped.
+func (d *jsonDecoder) offset() int {
+	offset := int(d.decoder.InputOffset())
+	for offset < len(d.content) && strings.IndexByte(" \t\r\n,:", d.content[offset]) >= 0 {
+		offset++
+	}
+
+	return offset
+}
+
+func (d *jsonDecoder) wrapError(offset int, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = int(syntaxErr.Offset)
+		if strings.HasPrefix(syntaxErr.Error(), "invalid character") {
+			// the offset is after the invalid character.
+			offset--
+		}
+	}
+
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return d.errorf(offset, "%w", err)
+}
+
+func (d *jsonDecoder) errorf(offset int, format string, a ...interface{}) error {
+	line, column := getLineColumn(d.content, offset)
+
+	return fmt.Errorf("json: line %d, column %d (offset %d): %w", line, column, offset, fmt.Errorf(format, a...))
+}
+
+// getLineColumn returns the line and the column (1-based) of the offset in the content.
+func getLineColumn(content []byte, offset int) (int, int) {
+	if offset > len(content) {
+		offset = len(content)
+	}
+
+	before := content[:offset]
+
+	return bytes.Count(before, []byte("\n")) + 1, offset - bytes.LastIndexByte(before, '\n')
+}
+
+// getJSONNumber converts a JSON number to an int, an uint64, or a float64,
+// the integers out of range are kept as strings.
+func getJSONNumber(number json.Number) interface{} {
+	s := number.String()
+
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil && i >= math.MinInt && i <= math.MaxInt {
+			return int(i)
+		}
+
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return u
+		}
+
+		return s
+	}
+
+	if f, err := number.Float64(); err == nil {
+		return f
+	}
+
+	return s
+}
@@ -0,0 +1,150 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decodeJSON(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected map[string]interface{}
+	}{
+		{
+			desc:     "empty content",
+			content:  " \n",
+			expected: map[string]interface{}{},
+		},
+		{
+			desc:    "simple values",
+			content: `{"foo": "bar", "enabled": true, "port": 8080, "ratio": 0.5, "none": null}`,
+			expected: map[string]interface{}{
+				"foo":     "bar",
+				"enabled": true,
+				"port":    8080,
+				"ratio":   0.5,
+				"none":    nil,
+			},
+		},
+		{
+			desc:    "large integers",
+			content: `{"max": 18446744073709551615, "big": 123456789012345678901234567890, "min": -9223372036854775808}`,
+			expected: map[string]interface{}{
+				"max": uint64(18446744073709551615),
+				"big": "123456789012345678901234567890",
+				"min": -9223372036854775808,
+			},
+		},
+		{
+			desc:    "nested values",
+			content: `{"yi": {"names": ["a", "b"], "servers": [{"url": "http://a"}]}}`,
+			expected: map[string]interface{}{
+				"yi": map[string]interface{}{
+					"names":   []interface{}{"a", "b"},
+					"servers": []interface{}{map[string]interface{}{"url": "http://a"}},
+				},
+			},
+		},
+		{
+			desc:    "same key in different objects",
+			content: `{"a": {"foo": 1}, "b": {"foo": 2}}`,
+			expected: map[string]interface{}{
+				"a": map[string]interface{}{"foo": 1},
+				"b": map[string]interface{}{"foo": 2},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := decodeJSON([]byte(test.content))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, data)
+		})
+	}
+}
+
+func Test_decodeJSON_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc: "duplicate key",
+			content: `{
+  "yi": {
+    "foo": "bar",
+    "foo": "baz"
+  }
+}`,
+			expected: `json: line 4, column 5 (offset 34): duplicate key "yi.foo"`,
+		},
+		{
+			desc:     "invalid character",
+			content:  "{\n  \"foo\": bar\n}",
+			expected: "json: line 2, column 10 (offset 11): invalid character 'b' looking for beginning of value",
+		},
+		{
+			desc:     "unexpected end",
+			content:  `{"foo": "bar"`,
+			expected: "json: line 1, column 14 (offset 13): unexpected end of JSON input",
+		},
+		{
+			desc:     "YAML content",
+			content:  "foo: bar",
+			expected: "json: line 1, column 2 (offset 1): invalid character 'o' in literal false (expecting 'a')",
+		},
+		{
+			desc:     "root array",
+			content:  `["foo"]`,
+			expected: "json: line 1, column 1 (offset 0): the root element must be an object",
+		},
+		{
+			desc:     "content after the root element",
+			content:  `{"foo": "bar"} {}`,
+			expected: "json: line 1, column 16 (offset 15): invalid content after the root element",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := decodeJSON([]byte(test.content))
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestDecodeContent_json(t *testing.T) {
+	content := `{
+  "max": 18446744073709551615,
+  "id": 123456789012345678901234567890,
+  "ports": [80, 443],
+  "raw": {"ports": [80, 443], "ratio": 0.5}
+}`
+
+	element := &struct {
+		Max   uint64
+		ID    string
+		Ports []int
+		Raw   map[string]interface{}
+	}{}
+
+	err := DecodeContent(content, ".json", element)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(18446744073709551615), element.Max)
+	assert.Equal(t, "123456789012345678901234567890", element.ID)
+	assert.Equal(t, []int{80, 443}, element.Ports)
+	assert.Equal(t, map[string]interface{}{"ports": []interface{}{int64(80), int64(443)}, "ratio": "0.500000"}, element.Raw)
+}
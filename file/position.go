@@ -0,0 +1,272 @@
+package file
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// position is the position of a key in a configuration file.
+type position struct {
+	line   int
+	column int
+}
+
+// positions holds the positions of the keys of a configuration file,
+// indexed by their lower case path (ex: servers[0].url).
+type positions map[string]position
+
+// get returns the position of the key located at path (ex: traefik.servers[0].url),
+// or, if unknown (ex: the elements of an inline array), the position of its closest parent.
+func (p positions) get(path string) (position, bool) {
+	key := strings.ToLower(path)
+
+	// the root node is not a key of the file.
+	if i := strings.IndexAny(key, ".["); i >= 0 && key[i] == '.' {
+		key = key[i+1:]
+	} else {
+		return position{}, false
+	}
+
+	for key != "" {
+		if pos, ok := p[key]; ok {
+			return pos, true
+		}
+
+		i := strings.LastIndexAny(key, ".[")
+		if i < 0 {
+			break
+		}
+		key = key[:i]
+	}
+
+	return position{}, false
+}
+
+/*
+YAML section
+*/
+
+// getYAMLPositions returns the positions of the keys of a YAML (or JSON) content.
+func getYAMLPositions(content []byte) positions {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil
+	}
+
+	pos := positions{}
+
+	for _, doc := range root.Content {
+		addYAMLPositions(pos, doc, "")
+	}
+
+	return pos
+}
+
+func addYAMLPositions(pos positions, node *yaml.Node, path string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			p := joinKey(path, strings.ToLower(key.Value))
+			pos[p] = position{line: key.Line, column: key.Column}
+
+			addYAMLPositions(pos, value, p)
+		}
+
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			p := path + "[" + strconv.Itoa(i) + "]"
+			pos[p] = position{line: item.Line, column: item.Column}
+
+			addYAMLPositions(pos, item, p)
+		}
+
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			addYAMLPositions(pos, node.Alias, path)
+		}
+	}
+}
+
+/*
+TOML section
+*/
+
+// getTOMLPositions returns the positions of the keys of a TOML content.
+// The keys of the inline tables get the position of the inline table.
+func getTOMLPositions(content []byte) positions {
+	pos := positions{}
+
+	// current index of the arrays of tables, by path.
+	arrays := map[string]int{}
+
+	var table string
+	var multiline string
+	var depth int
+
+	for i, line := range bytes.Split(content, []byte("\n")) {
+		text := string(line)
+
+		// inside a multi-line string or array.
+		if multiline != "" {
+			if strings.Contains(text, multiline) {
+				multiline = ""
+			}
+			continue
+		}
+
+		if depth > 0 {
+			depth += bracketDepth(text)
+			continue
+		}
+
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+
+		column := len(text) - len(strings.TrimLeft(text, " \t")) + 1
+
+		switch {
+		case strings.HasPrefix(trimmed, "[["):
+			keys, _ := splitTOMLKey(strings.TrimPrefix(trimmed, "[["), ']')
+			if len(keys) == 0 {
+				continue
+			}
+
+			base := resolveTOMLKeys(arrays, "", keys)
+
+			index := 0
+			if current, ok := arrays[base]; ok {
+				index = current + 1
+			}
+			arrays[base] = index
+
+			if _, ok := pos[base]; !ok {
+				pos[base] = position{line: i + 1, column: column}
+			}
+
+			table = base + "[" + strconv.Itoa(index) + "]"
+			pos[table] = position{line: i + 1, column: column}
+
+		case trimmed[0] == '[':
+			keys, _ := splitTOMLKey(trimmed[1:], ']')
+			if len(keys) == 0 {
+				continue
+			}
+
+			table = resolveTOMLKeys(arrays, "", keys)
+			pos[table] = position{line: i + 1, column: column}
+
+		default:
+			keys, rest := splitTOMLKey(trimmed, '=')
+			if len(keys) == 0 {
+				continue
+			}
+
+			pos[resolveTOMLKeys(arrays, table, keys)] = position{line: i + 1, column: column}
+
+			value := strings.TrimSpace(rest)
+			switch {
+			case strings.HasPrefix(value, `"""`) && !strings.Contains(value[3:], `"""`):
+				multiline = `"""`
+			case strings.HasPrefix(value, "'''") && !strings.Contains(value[3:], "'''"):
+				multiline = "'''"
+			case strings.HasPrefix(value, "["):
+				depth = bracketDepth(value)
+			}
+		}
+	}
+
+	return pos
+}
+
+// resolveTOMLKeys returns the path of the keys in the table, the current elements of the arrays of tables included.
+func resolveTOMLKeys(arrays map[string]int, table string, keys []string) string {
+	path := table
+	for i, key := range keys {
+		path = joinKey(path, strings.ToLower(key))
+
+		if index, ok := arrays[path]; ok && i < len(keys)-1 {
+			path += "[" + strconv.Itoa(index) + "]"
+		}
+	}
+
+	return path
+}
+
+// splitTOMLKey splits the dotted key at the beginning of the text, until the end character,
+// and returns the parts of the key and the rest of the text.
+func splitTOMLKey(text string, end byte) ([]string, string) {
+	var keys []string
+	var current strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case c == '"' || c == '\'':
+			closing := strings.IndexByte(text[i+1:], c)
+			if closing < 0 {
+				return nil, ""
+			}
+
+			current.WriteString(text[i+1 : i+1+closing])
+			i += closing + 1
+
+		case c == '.':
+			keys = append(keys, strings.TrimSpace(current.String()))
+			current.Reset()
+
+		case c == end:
+			keys = append(keys, strings.TrimSpace(current.String()))
+			return keys, text[i+1:]
+
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	return nil, ""
+}
+
+// bracketDepth returns the difference between the opening and the closing brackets of the text, outside the strings.
+func bracketDepth(text string) int {
+	var depth int
+	var quote byte
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return depth
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		}
+	}
+
+	return depth
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
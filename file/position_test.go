@@ -0,0 +1,241 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/parser"
+)
+
+func Test_getTOMLPositions(t *testing.T) {
+	content := `# comment
+foo = "bar"
+"quoted.key" = 1
+multi = """
+fake = 1
+"""
+list = [
+  "a",
+  "b",
+]
+
+[yi]
+  fii.fuu = "bur"
+
+[[servers]]
+  url = "http://a"
+
+[[servers]]
+  url = "http://b"
+  [servers.health]
+    path = "/ping"
+`
+
+	expected := positions{
+		"foo":                    {line: 2, column: 1},
+		"quoted.key":             {line: 3, column: 1},
+		"multi":                  {line: 4, column: 1},
+		"list":                   {line: 7, column: 1},
+		"yi":                     {line: 12, column: 1},
+		"yi.fii.fuu":             {line: 13, column: 3},
+		"servers":                {line: 15, column: 1},
+		"servers[0]":             {line: 15, column: 1},
+		"servers[0].url":         {line: 16, column: 3},
+		"servers[1]":             {line: 18, column: 1},
+		"servers[1].url":         {line: 19, column: 3},
+		"servers[1].health":      {line: 20, column: 3},
+		"servers[1].health.path": {line: 21, column: 5},
+	}
+
+	assert.Equal(t, expected, getTOMLPositions([]byte(content)))
+}
+
+func Test_getYAMLPositions(t *testing.T) {
+	content := `foo: bar
+yi:
+  fii: bir
+servers:
+  - url: http://a
+  - url: http://b
+    weight: 2
+`
+
+	expected := positions{
+		"foo":               {line: 1, column: 1},
+		"yi":                {line: 2, column: 1},
+		"yi.fii":            {line: 3, column: 3},
+		"servers":           {line: 4, column: 1},
+		"servers[0]":        {line: 5, column: 5},
+		"servers[0].url":    {line: 5, column: 5},
+		"servers[1]":        {line: 6, column: 5},
+		"servers[1].url":    {line: 6, column: 5},
+		"servers[1].weight": {line: 7, column: 5},
+	}
+
+	assert.Equal(t, expected, getYAMLPositions([]byte(content)))
+}
+
+func Test_positions_get(t *testing.T) {
+	pos := positions{
+		"yi":     {line: 2, column: 1},
+		"yi.fii": {line: 3, column: 3},
+	}
+
+	testCases := []struct {
+		desc     string
+		path     string
+		expected position
+		found    bool
+	}{
+		{
+			desc:     "exact path",
+			path:     "traefik.yi.fii",
+			expected: position{line: 3, column: 3},
+			found:    true,
+		},
+		{
+			desc:     "case insensitive",
+			path:     "traefik.Yi.Fii",
+			expected: position{line: 3, column: 3},
+			found:    true,
+		},
+		{
+			desc:     "closest parent",
+			path:     "traefik.yi.fuu[1]",
+			expected: position{line: 2, column: 1},
+			found:    true,
+		},
+		{
+			desc: "root",
+			path: "traefik",
+		},
+		{
+			desc: "unknown",
+			path: "traefik.foo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p, ok := pos.get(test.path)
+			assert.Equal(t, test.found, ok)
+			assert.Equal(t, test.expected, p)
+		})
+	}
+}
+
+func TestDecode_errorPosition(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		fileName string
+		content  string
+		opts     parser.DecodeOpts
+		expected string
+	}{
+		{
+			desc:     "YAML type error",
+			fileName: "config.yml",
+			content: `yi:
+  fii: bir
+  fuu: bur
+`,
+			expected: "config.yml:3:3",
+		},
+		{
+			desc:     "YAML unknown key",
+			fileName: "config.yml",
+			content: `yi:
+  fii: bir
+
+  foo: bar
+`,
+			opts:     parser.DecodeOpts{UnknownKeys: parser.UnknownKeysStrict},
+			expected: "config.yml:4:3",
+		},
+		{
+			desc:     "TOML type error",
+			fileName: "config.toml",
+			content: `
+[yi]
+  fii = "bir"
+  fuu = "bur"
+`,
+			expected: "config.toml:4:3",
+		},
+		{
+			desc:     "TOML unknown key",
+			fileName: "config.toml",
+			content: `
+[yi]
+  fii = "bir"
+  foo = "bar"
+`,
+			opts:     parser.DecodeOpts{UnknownKeys: parser.UnknownKeysStrict},
+			expected: "config.toml:4:3",
+		},
+		{
+			desc:     "JSON type error",
+			fileName: "config.json",
+			content: `{
+  "yi": {
+    "fuu": "bur"
+  }
+}`,
+			expected: "config.json:3:5",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			filePath := filepath.Join(t.TempDir(), test.fileName)
+
+			err := os.WriteFile(filePath, []byte(test.content), 0o600)
+			require.NoError(t, err)
+
+			element := &struct {
+				Yi struct {
+					Fii string
+					Fuu int
+				}
+			}{}
+
+			err = DecodeWithOpts(filePath, element, test.opts)
+			require.Error(t, err)
+
+			dErrs := parser.AsDecodeErrors(err)
+			require.Len(t, dErrs, 1)
+
+			assert.Equal(t, filepath.Join(filepath.Dir(filePath), test.expected), dErrs[0].Origin)
+			assert.Contains(t, err.Error(), test.expected)
+		})
+	}
+}
+
+func TestDecodeContent_errorPosition(t *testing.T) {
+	content := `yi:
+  fuu: bur
+`
+
+	element := &struct {
+		Yi struct {
+			Fuu int
+		}
+	}{}
+
+	err := DecodeContent(content, ".yaml", element)
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, "file:2:3", dErrs[0].Origin)
+}
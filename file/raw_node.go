@@ -1,6 +1,7 @@
 package file
 
 import (
+	"encoding"
 	"fmt"
 	"reflect"
 	"sort"
@@ -39,6 +40,18 @@ func decodeRaw(node *parser.Node, vData reflect.Value, filters ...string) error
 
 		child := &parser.Node{Name: key.String()}
 
+		if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+			// ex: TOML date-time values.
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				return err
+			}
+
+			child.Value = string(text)
+			node.Children = append(node.Children, child)
+			continue
+		}
+
 		switch value.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			fallthrough
@@ -2,6 +2,8 @@
 package flag
 
 import (
+	"strings"
+
 	"github.com/traefik/paerser/parser"
 )
 
@@ -12,12 +14,61 @@ import (
 // - untyped nodes -> nodes augmented with metadata such as kind (inferred from element)
 // - "typed" nodes -> typed element.
 func Decode(args []string, element interface{}) error {
-	ref, err := Parse(args, element)
+	return DecodeWithOpts(args, element, parser.DecodeOpts{})
+}
+
+// DecodeWithOpts decodes the given flag arguments into the given element, according to the options.
+func DecodeWithOpts(args []string, element interface{}, opts parser.DecodeOpts) error {
+	f, err := parse(args, element)
 	if err != nil {
 		return err
 	}
 
-	return parser.Decode(ref, element, parser.DefaultRootName)
+	if onDeprecation := opts.OnDeprecation; onDeprecation != nil {
+		opts.OnDeprecation = func(d parser.Deprecation) {
+			d.Source = "flag"
+			onDeprecation(d)
+		}
+	}
+
+	node, err := decodeToNode(f)
+	if err != nil {
+		return err
+	}
+
+	err = parser.DecodeLabelNode(element, node, opts)
+	for _, dErr := range parser.AsDecodeErrors(err) {
+		dErr.Origin = "flag"
+
+		if dErr.Suggestion != "" {
+			dErr.Suggestion = "--" + strings.TrimPrefix(dErr.Suggestion, parser.DefaultRootName+".")
+		}
+	}
+
+	return err
+}
+
+// DecodeToNode decodes the given flag arguments into a tree of untyped nodes, holding the source of their values.
+func DecodeToNode(args []string, element interface{}) (*parser.Node, error) {
+	f, err := parse(args, element)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeToNode(f)
+}
+
+func decodeToNode(f *flagSet) (*parser.Node, error) {
+	node, err := parser.DecodeToNode(f.values, parser.DefaultRootName)
+	if err != nil {
+		return nil, err
+	}
+
+	parser.SetSources(node, func(path string) *parser.Source {
+		return f.sources[strings.ToLower(path)]
+	})
+
+	return node, nil
 }
 
 // Encode encodes the configuration in element into the flags represented in the returned Flats.
@@ -30,7 +81,7 @@ func Encode(element interface{}) ([]parser.Flat, error) {
 		return nil, nil
 	}
 
-	etnOpts := parser.EncoderToNodeOpts{OmitEmpty: false, TagName: parser.TagLabel, AllowSliceAsStruct: true}
+	etnOpts := parser.EncoderToNodeOpts{OmitEmpty: false, TagName: parser.TagLabel, AllowSliceAsStruct: true, Redact: true}
 	node, err := parser.EncodeToNode(element, parser.DefaultRootName, etnOpts)
 	if err != nil {
 		return nil, err
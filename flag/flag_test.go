@@ -1,6 +1,8 @@
 package flag
 
 import (
+	"net"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -363,6 +365,24 @@ func TestDecode(t *testing.T) {
 				Foo: "--bar",
 			},
 		},
+		{
+			desc: "custom type values",
+			args: []string{"--prefix", "10.0.0.0/8", "--ip=10.0.0.1", "--trusted=10.0.0.0/8,192.168.0.0/16"},
+			element: &struct {
+				Prefix  *netip.Prefix
+				IP      net.IP
+				Trusted []netip.Prefix
+			}{},
+			expected: &struct {
+				Prefix  *netip.Prefix
+				IP      net.IP
+				Trusted []netip.Prefix
+			}{
+				Prefix:  func() *netip.Prefix { p := netip.MustParsePrefix("10.0.0.0/8"); return &p }(),
+				IP:      net.ParseIP("10.0.0.1"),
+				Trusted: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")},
+			},
+		},
 		{
 			desc: "struct pointer value",
 			args: []string{"--foo"},
@@ -902,6 +922,40 @@ func TestEncode(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "custom type fields",
+			element: &struct {
+				Prefix  netip.Prefix          `description:"prefix description"`
+				Trusted []netip.Prefix        `description:"trusted description"`
+				IP      *net.IP               `description:"ip description"`
+				Addrs   map[string]netip.Addr `description:"addrs description"`
+			}{
+				Prefix:  netip.MustParsePrefix("10.0.0.0/8"),
+				Trusted: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")},
+			},
+			expected: []parser.Flat{
+				{
+					Name:        "addrs.<name>",
+					Description: "addrs description",
+					Default:     "",
+				},
+				{
+					Name:        "ip",
+					Description: "ip description",
+					Default:     "",
+				},
+				{
+					Name:        "prefix",
+					Description: "prefix description",
+					Default:     "10.0.0.0/8",
+				},
+				{
+					Name:        "trusted",
+					Description: "trusted description",
+					Default:     "10.0.0.0/8, 192.168.0.0/16",
+				},
+			},
+		},
 		// Skipped: because realistically not needed in Traefik for now.
 		// {
 		// 	desc: "map of map field level 2",
@@ -938,3 +992,152 @@ func TestEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecode_decodeError(t *testing.T) {
+	element := &struct {
+		Foo struct {
+			Bar int
+		}
+	}{}
+
+	err := Decode([]string{"--foo.bar=baz"}, element)
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, "traefik.foo.bar", dErrs[0].Path)
+	assert.Equal(t, "baz", dErrs[0].Value)
+	assert.Equal(t, "flag", dErrs[0].Origin)
+}
+
+func TestDecodeWithOpts_aggregateErrors(t *testing.T) {
+	element := &struct {
+		Foo struct {
+			Bar int
+			Baz string
+		}
+		Fuu int
+	}{}
+
+	err := DecodeWithOpts([]string{"--foo.bar=baz", "--foo.baz=qux", "--fuu=bar"}, element, parser.DecodeOpts{AggregateErrors: true})
+	require.Error(t, err)
+
+	var paths []string
+	for _, dErr := range parser.AsDecodeErrors(err) {
+		assert.Equal(t, "flag", dErr.Origin)
+		paths = append(paths, dErr.Path)
+	}
+
+	assert.Equal(t, []string{"traefik.foo.bar", "traefik.fuu"}, paths)
+	assert.Equal(t, "qux", element.Foo.Baz)
+}
+
+func TestDecodeWithOpts_unknownKeys(t *testing.T) {
+	element := &struct {
+		Foo string
+	}{}
+
+	err := DecodeWithOpts([]string{"--foo=bar", "--fuu=baz"}, element, parser.DecodeOpts{UnknownKeys: parser.UnknownKeysIgnore})
+	require.NoError(t, err)
+
+	assert.Equal(t, "bar", element.Foo)
+
+	err = DecodeWithOpts([]string{"--foo=bar", "--fuu=baz"}, element, parser.DecodeOpts{UnknownKeys: parser.UnknownKeysStrict})
+	require.ErrorIs(t, err, parser.ErrFieldNotFound)
+}
+
+func TestDecode_suggestion(t *testing.T) {
+	element := &struct {
+		EntryPoints map[string]*struct {
+			Address string
+		}
+	}{}
+
+	err := Decode([]string{"--entrypoints.web.adress=:80"}, element)
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, "--entryPoints.web.address", dErrs[0].Suggestion)
+	assert.Contains(t, err.Error(), `did you mean "--entryPoints.web.address"?`)
+}
+
+func TestDecodeWithOpts_aliases(t *testing.T) {
+	element := &struct {
+		Foo bool   `description:"foo description" alias:"oldFoo"`
+		Fii string `description:"fii description" alias:"oldFii"`
+	}{}
+
+	var deprecations []parser.Deprecation
+	opts := parser.DecodeOpts{
+		OnDeprecation: func(d parser.Deprecation) {
+			deprecations = append(deprecations, d)
+		},
+	}
+
+	err := DecodeWithOpts([]string{"--oldfoo", "--oldfii=bar"}, element, opts)
+	require.NoError(t, err)
+
+	assert.True(t, element.Foo)
+	assert.Equal(t, "bar", element.Fii)
+
+	expectedDeprecations := []parser.Deprecation{
+		{OldPath: "traefik.oldfii", NewPath: "traefik.fii", Source: "flag"},
+		{OldPath: "traefik.oldfoo", NewPath: "traefik.foo", Source: "flag"},
+	}
+	assert.Equal(t, expectedDeprecations, deprecations)
+
+	flats, err := Encode(element)
+	require.NoError(t, err)
+
+	expectedFlats := []parser.Flat{
+		{Name: "fii", Description: "fii description", Default: "bar"},
+		{Name: "foo", Description: "foo description", Default: "true"},
+	}
+	assert.Equal(t, expectedFlats, flats)
+}
+
+func TestDecode_source(t *testing.T) {
+	element := &struct {
+		Foo bool
+		Fii struct {
+			Bar int
+		}
+	}{}
+
+	err := Decode([]string{"--foo", "--fii.bar", "baz"}, element)
+	require.Error(t, err)
+
+	dErrs := parser.AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, &parser.Source{Kind: parser.SourceFlag, Name: "--fii.bar", Index: 2}, dErrs[0].Source)
+}
+
+func TestDecodeWithOpts_interpolate(t *testing.T) {
+	element := &struct {
+		Foo   string
+		Names []string
+	}{}
+
+	opts := parser.DecodeOpts{
+		Interpolate: true,
+		LookupEnv: func(name string) (string, bool) {
+			if name == "FOO" {
+				return "foo", true
+			}
+			return "", false
+		},
+	}
+
+	err := DecodeWithOpts([]string{"--foo=${FOO}", "--names=${FOO},${BAR:-bar}"}, element, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", element.Foo)
+	assert.Equal(t, []string{"foo", "bar"}, element.Names)
+
+	err = DecodeWithOpts([]string{"--foo=${BAR}"}, element, opts)
+	assert.EqualError(t, err, "traefik.foo (flag): missing variable: BAR")
+}
@@ -13,11 +13,22 @@ import (
 // using the type information in element to discriminate whether a flag is supposed to be a bool,
 // and other such ambiguities.
 func Parse(args []string, element interface{}) (map[string]string, error) {
-	f := flagSet{
+	f, err := parse(args, element)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.values, nil
+}
+
+func parse(args []string, element interface{}) (*flagSet, error) {
+	f := &flagSet{
 		flagTypes: getFlagTypes(element),
 		args:      args,
+		nbArgs:    len(args),
 		values:    make(map[string]string),
 		keys:      make(map[string]string),
+		sources:   make(map[string]*parser.Source),
 	}
 
 	for {
@@ -30,14 +41,16 @@ func Parse(args []string, element interface{}) (map[string]string, error) {
 		}
 		return nil, err
 	}
-	return f.values, nil
+	return f, nil
 }
 
 type flagSet struct {
 	flagTypes map[string]reflect.Kind
 	args      []string
+	nbArgs    int
 	values    map[string]string
 	keys      map[string]string
+	sources   map[string]*parser.Source
 }
 
 func (f *flagSet) parseOne() (bool, error) {
@@ -64,6 +77,7 @@ func (f *flagSet) parseOne() (bool, error) {
 	}
 
 	// it's a flag. does it have an argument?
+	index := f.nbArgs - len(f.args) + 1
 	f.args = f.args[1:]
 	hasValue := false
 	value := ""
@@ -77,13 +91,13 @@ func (f *flagSet) parseOne() (bool, error) {
 	}
 
 	if hasValue {
-		f.setValue(name, value)
+		f.setValue(name, value, index)
 		return true, nil
 	}
 
 	flagType := f.getFlagType(name)
 	if flagType == reflect.Bool || flagType == reflect.Pointer {
-		f.setValue(name, "true")
+		f.setValue(name, "true", index)
 		return true, nil
 	}
 
@@ -97,17 +111,18 @@ func (f *flagSet) parseOne() (bool, error) {
 		return false, fmt.Errorf("flag needs an argument: -%s", name)
 	}
 
-	f.setValue(name, value)
+	f.setValue(name, value, index)
 	return true, nil
 }
 
-func (f *flagSet) setValue(name, value string) {
+func (f *flagSet) setValue(name, value string, index int) {
 	srcKey := parser.DefaultRootName + "." + name
 	neutralKey := strings.ToLower(srcKey)
 
 	key, ok := f.keys[neutralKey]
 	if !ok {
 		f.keys[neutralKey] = srcKey
+		f.sources[neutralKey] = &parser.Source{Kind: parser.SourceFlag, Name: "--" + name, Index: index}
 		key = srcKey
 	}
 
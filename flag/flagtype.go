@@ -22,6 +22,11 @@ func getFlagTypes(element interface{}) map[string]reflect.Kind {
 }
 
 func addFlagType(ref map[string]reflect.Kind, name string, typ reflect.Type) {
+	if parser.IsCustomType(typ) {
+		// a custom type always needs a value.
+		return
+	}
+
 	switch typ.Kind() {
 	case reflect.Bool, reflect.Slice:
 		ref[name] = typ.Kind()
@@ -30,7 +35,7 @@ func addFlagType(ref map[string]reflect.Kind, name string, typ reflect.Type) {
 		addFlagType(ref, getName(name, parser.MapNamePlaceholder), typ.Elem())
 
 	case reflect.Pointer:
-		if typ.Elem().Kind() == reflect.Struct {
+		if typ.Elem().Kind() == reflect.Struct && !parser.IsCustomType(typ.Elem()) {
 			ref[name] = typ.Kind()
 		}
 		addFlagType(ref, name, typ.Elem())
@@ -45,8 +50,13 @@ func addFlagType(ref map[string]reflect.Kind, name string, typ reflect.Type) {
 
 			if subField.Anonymous {
 				addFlagType(ref, getName(name), subField.Type)
-			} else {
-				addFlagType(ref, getName(name, subField.Name), subField.Type)
+				continue
+			}
+
+			addFlagType(ref, getName(name, subField.Name), subField.Type)
+
+			for _, alias := range parser.GetAliases(subField) {
+				addFlagType(ref, getName(name, alias), subField.Type)
 			}
 		}
 
@@ -1,6 +1,8 @@
 package flag
 
 import (
+	"net"
+	"net/netip"
 	"reflect"
 	"testing"
 
@@ -198,6 +200,37 @@ func Test_getFlagTypes(t *testing.T) {
 				"foo." + parser.MapNamePlaceholder: reflect.Slice,
 			},
 		},
+		{
+			desc: "custom types",
+			element: &struct {
+				Foo netip.Prefix
+				Fii *netip.Prefix
+				Fuu net.IP
+				Fee []netip.Prefix
+				Bar map[string]*netip.Addr
+			}{},
+			expected: map[string]reflect.Kind{
+				"fee": reflect.Slice,
+			},
+		},
+		{
+			desc: "aliases",
+			element: &struct {
+				Foo bool `alias:"oldFoo,olderFoo"`
+				Fii struct {
+					Fuu bool `alias:"oldFuu"`
+				} `alias:"oldFii"`
+			}{},
+			expected: map[string]reflect.Kind{
+				"foo":           reflect.Bool,
+				"oldfoo":        reflect.Bool,
+				"olderfoo":      reflect.Bool,
+				"fii.fuu":       reflect.Bool,
+				"fii.oldfuu":    reflect.Bool,
+				"oldfii.fuu":    reflect.Bool,
+				"oldfii.oldfuu": reflect.Bool,
+			},
+		},
 		{
 			desc: "embedded struct",
 			element: &struct {
@@ -11,53 +11,70 @@ type initializer interface {
 	SetDefaults()
 }
 
-// Generate recursively initializes an empty structure, calling SetDefaults on each field, when it applies.
+// Generate recursively initializes an empty structure, applying the default tags and calling SetDefaults on each field, when it applies.
+// The invalid default tags are ignored (see GenerateWithError).
 func Generate(element interface{}) {
+	_ = GenerateWithError(element)
+}
+
+// GenerateWithError is like Generate, but it returns the first error of the invalid default tags.
+// The whole structure is initialized anyway.
+func GenerateWithError(element interface{}) error {
 	if element == nil {
-		return
+		return nil
 	}
 
-	generate(element)
+	return generate(element)
 }
 
-func generate(element interface{}) {
+func generate(element interface{}) error {
 	field := reflect.ValueOf(element)
 
-	fill(field)
+	return fill(field)
 }
 
-func fill(field reflect.Value) {
+func fill(field reflect.Value) error {
+	if parser.IsCustomType(field.Type()) {
+		return nil
+	}
+
 	switch field.Kind() {
 	case reflect.Pointer:
-		setPtr(field)
+		return setPtr(field)
 	case reflect.Struct:
-		setStruct(field)
+		return setStruct(field)
 	case reflect.Map:
-		setMap(field)
+		return setMap(field)
 	case reflect.Slice:
-		if field.Type().Elem().Kind() == reflect.Struct ||
-			field.Type().Elem().Kind() == reflect.Pointer && field.Type().Elem().Elem().Kind() == reflect.Struct {
+		if !parser.IsCustomType(field.Type().Elem()) && (field.Type().Elem().Kind() == reflect.Struct ||
+			field.Type().Elem().Kind() == reflect.Pointer && field.Type().Elem().Elem().Kind() == reflect.Struct) {
 			slice := reflect.MakeSlice(field.Type(), 1, 1)
 			field.Set(slice)
 
 			// use Ptr to allow "SetDefaults"
 			value := reflect.New(reflect.PointerTo(field.Type().Elem()))
-			setPtr(value)
+			err := setPtr(value)
 
 			elem := value.Elem().Elem()
 			field.Index(0).Set(elem)
+
+			return err
 		} else if field.Len() == 0 {
 			slice := reflect.MakeSlice(field.Type(), 0, 0)
 			field.Set(slice)
 		}
 	}
+
+	return nil
 }
 
-func setPtr(field reflect.Value) {
+func setPtr(field reflect.Value) error {
 	if field.IsNil() {
 		field.Set(reflect.New(field.Type().Elem()))
 	}
 
+	err := parser.ApplyDefaults(field.Interface())
+
 	if field.Type().Implements(reflect.TypeOf((*initializer)(nil)).Elem()) {
 		method := field.MethodByName("SetDefaults")
 		if method.IsValid() {
@@ -65,10 +82,16 @@ func setPtr(field reflect.Value) {
 		}
 	}
 
-	fill(field.Elem())
+	if fErr := fill(field.Elem()); err == nil {
+		err = fErr
+	}
+
+	return err
 }
 
-func setStruct(field reflect.Value) {
+func setStruct(field reflect.Value) error {
+	var err error
+
 	for i := 0; i < field.NumField(); i++ {
 		fd := field.Field(i)
 		structField := field.Type().Field(i)
@@ -78,20 +101,26 @@ func setStruct(field reflect.Value) {
 		}
 
 		if parser.IsExported(structField) {
-			fill(fd)
+			if fErr := fill(fd); err == nil {
+				err = fErr
+			}
 		}
 	}
+
+	return err
 }
 
-func setMap(field reflect.Value) {
+func setMap(field reflect.Value) error {
 	if field.IsNil() {
 		field.Set(reflect.MakeMap(field.Type()))
 	}
 
 	ptrValue := reflect.New(reflect.PointerTo(field.Type().Elem()))
-	fill(ptrValue)
+	err := fill(ptrValue)
 
 	value := ptrValue.Elem().Elem()
 	key := reflect.ValueOf(parser.MapNamePlaceholder)
 	field.SetMapIndex(key, value)
+
+	return err
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/paerser/parser"
 )
 
@@ -369,6 +370,23 @@ func Test_generate(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:    "default tags",
+			element: &Ha{},
+			expected: &Ha{
+				Foo: "ha",
+				Fii: &Ho{
+					Field: "hi",
+					Port:  80,
+				},
+				Fee: map[string]Ho{
+					parser.MapNamePlaceholder: {
+						Field: "hi",
+						Port:  80,
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -376,7 +394,8 @@ func Test_generate(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			generate(test.element)
+			err := generate(test.element)
+			require.NoError(t, err)
 
 			assert.Equal(t, test.expected, test.element)
 		})
@@ -402,6 +421,44 @@ func (h *Hi) SetDefaults() {
 	h.Field = "hi"
 }
 
+func TestGenerate_invalidDefault(t *testing.T) {
+	element := &struct {
+		Foo *struct {
+			Port int `default:"eighty"`
+		}
+		Bar *struct {
+			Field string `default:"ho"`
+		}
+	}{}
+
+	err := GenerateWithError(element)
+	require.Error(t, err)
+
+	var dErr *parser.DecodeError
+	require.ErrorAs(t, err, &dErr)
+	assert.Equal(t, "Port", dErr.Path)
+	assert.Equal(t, "eighty", dErr.Value)
+
+	// the whole structure is initialized anyway.
+	require.NotNil(t, element.Bar)
+	assert.Equal(t, "ho", element.Bar.Field)
+}
+
+type Ha struct {
+	Foo string `default:"ha"`
+	Fii *Ho
+	Fee map[string]Ho
+}
+
+type Ho struct {
+	Field string `default:"ho"`
+	Port  int    `default:"80"`
+}
+
+func (h *Ho) SetDefaults() {
+	h.Field = "hi"
+}
+
 type Ya struct {
 	Foo     *Yaa
 	Field1  string
@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/traefik/paerser/types"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// TypeCodec holds the functions used to decode and encode the values of a custom type.
+type TypeCodec struct {
+	// Decode converts a raw value to a value of the custom type (mandatory).
+	Decode func(value string) (interface{}, error)
+	// Encode converts a value of the custom type to a raw value (optional).
+	// If not set, the value is encoded according to its kind.
+	Encode func(value interface{}) (string, error)
+}
+
+var codecs = struct {
+	sync.RWMutex
+	types map[reflect.Type]TypeCodec
+}{types: map[reflect.Type]TypeCodec{}}
+
+func init() {
+	RegisterType(reflect.TypeOf(types.Duration(0)), TypeCodec{
+		Decode: func(value string) (interface{}, error) {
+			d, err := parseDuration(value, time.Second)
+			return types.Duration(d), err
+		},
+		Encode: func(value interface{}) (string, error) {
+			d := time.Duration(value.(types.Duration))
+			if d%time.Second != 0 {
+				return d.String(), nil
+			}
+			return strconv.FormatInt(int64(d/time.Second), 10), nil
+		},
+	})
+
+	RegisterType(reflect.TypeOf(time.Duration(0)), TypeCodec{
+		Decode: func(value string) (interface{}, error) {
+			return parseDuration(value, time.Nanosecond)
+		},
+		Encode: func(value interface{}) (string, error) {
+			return value.(time.Duration).String(), nil
+		},
+	})
+
+	// url.URL only implements encoding.BinaryMarshaler.
+	RegisterType(reflect.TypeOf(url.URL{}), TypeCodec{
+		Decode: func(value string) (interface{}, error) {
+			u, err := url.Parse(value)
+			if err != nil {
+				return nil, err
+			}
+			return *u, nil
+		},
+		Encode: func(value interface{}) (string, error) {
+			u := value.(url.URL)
+			return u.String(), nil
+		},
+	})
+}
+
+// RegisterType registers the codec used to decode and encode the values of typ.
+// A registered codec takes precedence over the encoding.TextUnmarshaler and encoding.TextMarshaler implementations,
+// and over the handling based on the kind of typ.
+// Registering a type twice replaces the previous codec.
+func RegisterType(typ reflect.Type, codec TypeCodec) {
+	if typ == nil || codec.Decode == nil {
+		panic("parser: RegisterType requires a type and a decode function")
+	}
+
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	codecs.types[typ] = codec
+}
+
+func lookupCodec(typ reflect.Type) (TypeCodec, bool) {
+	codecs.RLock()
+	defer codecs.RUnlock()
+
+	codec, ok := codecs.types[typ]
+	return codec, ok
+}
+
+// IsCustomType reports whether typ is handled as a single value (a leaf) whatever its kind,
+// i.e. a codec is registered for the type (see RegisterType), or the type implements encoding.TextUnmarshaler.
+func IsCustomType(typ reflect.Type) bool {
+	if typ == nil || typ.Kind() == reflect.Pointer {
+		return false
+	}
+
+	if _, ok := lookupCodec(typ); ok {
+		return true
+	}
+
+	return reflect.PointerTo(typ).Implements(textUnmarshalerType)
+}
+
+func isCustomTypeOrPtr(typ reflect.Type) bool {
+	return IsCustomType(typ) || typ.Kind() == reflect.Pointer && IsCustomType(typ.Elem())
+}
+
+// setCustom sets the value of a custom type field from its raw representation.
+// The field must be addressable.
+func setCustom(field reflect.Value, value string) error {
+	if codec, ok := lookupCodec(field.Type()); ok {
+		val, err := codec.Decode(value)
+		if err != nil {
+			return err
+		}
+
+		rValue := reflect.ValueOf(val)
+		if !rValue.IsValid() || !rValue.Type().ConvertibleTo(field.Type()) {
+			return fmt.Errorf("invalid decoded value type: %T (expected %s)", val, field.Type())
+		}
+
+		field.Set(rValue.Convert(field.Type()))
+		return nil
+	}
+
+	unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler)
+	if !ok {
+		return fmt.Errorf("unsupported type: %s", field.Type())
+	}
+
+	return unmarshaler.UnmarshalText([]byte(value))
+}
+
+// isCustomMarshaler reports whether the custom type typ has its own raw representation.
+func isCustomMarshaler(typ reflect.Type) bool {
+	if !IsCustomType(typ) {
+		return false
+	}
+
+	if codec, ok := lookupCodec(typ); ok {
+		return codec.Encode != nil
+	}
+
+	return typ.Implements(textMarshalerType) || reflect.PointerTo(typ).Implements(textMarshalerType)
+}
+
+// EncodeCustomValue returns the raw representation of a value of a custom type (see IsCustomType and RegisterType).
+// It returns false if the type has no encoder, then the value is expected to be encoded according to its kind.
+func EncodeCustomValue(rValue reflect.Value) (string, bool, error) {
+	if !isCustomMarshaler(rValue.Type()) {
+		return "", false, nil
+	}
+
+	value, err := getCustomValue(rValue)
+	if err != nil {
+		return "", false, err
+	}
+
+	return value, true, nil
+}
+
+// getCustomValue returns the raw representation of a custom type value.
+func getCustomValue(rValue reflect.Value) (string, error) {
+	if codec, ok := lookupCodec(rValue.Type()); ok && codec.Encode != nil {
+		return codec.Encode(rValue.Interface())
+	}
+
+	if !rValue.Type().Implements(textMarshalerType) {
+		// the method has a pointer receiver.
+		ptr := reflect.New(rValue.Type())
+		ptr.Elem().Set(rValue)
+		rValue = ptr
+	}
+
+	text, err := rValue.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return "", err
+	}
+
+	return string(text), nil
+}
+
+// parseDuration parses a duration, a value without unit is expressed in defaultUnit.
+func parseDuration(value string, defaultUnit time.Duration) (time.Duration, error) {
+	val, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		return time.Duration(val) * defaultUnit, nil
+	}
+
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,251 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/types"
+)
+
+type ByteSize uint64
+
+type Percentage float64
+
+func init() {
+	RegisterType(reflect.TypeOf(ByteSize(0)), TypeCodec{
+		Decode: func(value string) (interface{}, error) {
+			units := map[string]uint64{"KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30}
+			for suffix, unit := range units {
+				if strings.HasSuffix(value, suffix) {
+					v, err := strconv.ParseUint(strings.TrimSuffix(value, suffix), 10, 64)
+					return ByteSize(v * unit), err
+				}
+			}
+
+			v, err := strconv.ParseUint(value, 10, 64)
+			return ByteSize(v), err
+		},
+		Encode: func(value interface{}) (string, error) {
+			v := value.(ByteSize)
+			if v%(1<<20) == 0 {
+				return fmt.Sprintf("%dMB", v>>20), nil
+			}
+			return strconv.FormatUint(uint64(v), 10), nil
+		},
+	})
+
+	RegisterType(reflect.TypeOf(Percentage(0)), TypeCodec{
+		Decode: func(value string) (interface{}, error) {
+			if !strings.HasSuffix(value, "%") {
+				return nil, errors.New("missing percent sign")
+			}
+			v, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+			return v / 100, err
+		},
+	})
+}
+
+func TestIsCustomType(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		typ      reflect.Type
+		expected bool
+	}{
+		{
+			desc: "nil",
+		},
+		{
+			desc: "string",
+			typ:  reflect.TypeOf(""),
+		},
+		{
+			desc:     "registered type",
+			typ:      reflect.TypeOf(ByteSize(0)),
+			expected: true,
+		},
+		{
+			desc:     "built-in registered type",
+			typ:      reflect.TypeOf(types.Duration(0)),
+			expected: true,
+		},
+		{
+			desc:     "TextUnmarshaler",
+			typ:      reflect.TypeOf(netip.Prefix{}),
+			expected: true,
+		},
+		{
+			desc:     "URL",
+			typ:      reflect.TypeOf(url.URL{}),
+			expected: true,
+		},
+		{
+			desc: "pointer",
+			typ:  reflect.TypeOf(&netip.Prefix{}),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, IsCustomType(test.typ))
+		})
+	}
+}
+
+type Limits struct {
+	Size     ByteSize              `description:"size description"`
+	Sizes    []ByteSize            `description:"sizes description"`
+	Ratio    *Percentage           `description:"ratio description"`
+	PerHost  map[string]ByteSize   `description:"per host description"`
+	Timeout  time.Duration         `description:"timeout description"`
+	Interval types.Duration        `description:"interval description"`
+	Delays   []types.Duration      `description:"delays description"`
+	Others   map[string]Percentage `description:"others description"`
+}
+
+func TestRegisterType_decode(t *testing.T) {
+	labels := map[string]string{
+		"traefik.size":        "2MB",
+		"traefik.sizes":       "1KB,3",
+		"traefik.ratio":       "50%",
+		"traefik.perhost.foo": "1GB",
+		"traefik.timeout":     "10",
+		"traefik.interval":    "10",
+		"traefik.delays":      "1,2m",
+	}
+
+	element := &Limits{}
+	err := Decode(labels, element, DefaultRootName)
+	require.NoError(t, err)
+
+	ratio := Percentage(0.5)
+	expected := &Limits{
+		Size:     2 << 20,
+		Sizes:    []ByteSize{1 << 10, 3},
+		Ratio:    &ratio,
+		PerHost:  map[string]ByteSize{"foo": 1 << 30},
+		Timeout:  10 * time.Nanosecond,
+		Interval: types.Duration(10 * time.Second),
+		Delays:   []types.Duration{types.Duration(time.Second), types.Duration(2 * time.Minute)},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestRegisterType_decodeError(t *testing.T) {
+	element := &Limits{}
+	err := Decode(map[string]string{"traefik.ratio": "50"}, element, DefaultRootName)
+	require.Error(t, err)
+}
+
+func TestRegisterType_encode(t *testing.T) {
+	ratio := Percentage(0.5)
+	element := &Limits{
+		Size:     2 << 20,
+		Sizes:    []ByteSize{1 << 10, 3 << 20},
+		Ratio:    &ratio,
+		PerHost:  map[string]ByteSize{"foo": 1 << 30},
+		Timeout:  1500 * time.Millisecond,
+		Interval: types.Duration(1500 * time.Millisecond),
+		Delays:   []types.Duration{types.Duration(time.Second), types.Duration(2 * time.Minute)},
+	}
+
+	labels, err := Encode(element, DefaultRootName)
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"traefik.Size":        "2MB",
+		"traefik.Sizes":       "1024, 3MB",
+		"traefik.Ratio":       "0.500000",
+		"traefik.PerHost.foo": "1024MB",
+		"traefik.Timeout":     "1.5s",
+		"traefik.Interval":    "1.5s",
+		"traefik.Delays":      "1, 120",
+	}
+	assert.Equal(t, expected, labels)
+
+	decoded := &Limits{}
+	err = Decode(map[string]string{
+		"traefik.Size":     labels["traefik.Size"],
+		"traefik.Interval": labels["traefik.Interval"],
+		"traefik.Delays":   labels["traefik.Delays"],
+	}, decoded, DefaultRootName)
+	require.NoError(t, err)
+
+	assert.Equal(t, element.Size, decoded.Size)
+	assert.Equal(t, element.Interval, decoded.Interval)
+	assert.Equal(t, element.Delays, decoded.Delays)
+}
+
+func TestURL(t *testing.T) {
+	type Endpoints struct {
+		URL   *url.URL
+		Other url.URL
+		URLs  []url.URL
+	}
+
+	labels := map[string]string{
+		"traefik.url":   "http://example.com/foo?bar=1",
+		"traefik.other": "https://user@example.org:8443",
+		"traefik.urls":  "http://a.com,http://b.com",
+	}
+
+	element := &Endpoints{}
+	err := Decode(labels, element, DefaultRootName)
+	require.NoError(t, err)
+
+	expected := &Endpoints{
+		URL:   &url.URL{Scheme: "http", Host: "example.com", Path: "/foo", RawQuery: "bar=1"},
+		Other: url.URL{Scheme: "https", User: url.User("user"), Host: "example.org:8443"},
+		URLs:  []url.URL{{Scheme: "http", Host: "a.com"}, {Scheme: "http", Host: "b.com"}},
+	}
+	assert.Equal(t, expected, element)
+
+	encoded, err := Encode(element, DefaultRootName)
+	require.NoError(t, err)
+
+	expectedLabels := map[string]string{
+		"traefik.URL":   "http://example.com/foo?bar=1",
+		"traefik.Other": "https://user@example.org:8443",
+		"traefik.URLs":  "http://a.com, http://b.com",
+	}
+	assert.Equal(t, expectedLabels, encoded)
+
+	err = Decode(map[string]string{"traefik.url": "http://[::1"}, &Endpoints{}, DefaultRootName)
+	require.Error(t, err)
+}
+
+func TestRegisterType_encodeToFlat(t *testing.T) {
+	element := &Limits{
+		Size:     2 << 20,
+		Interval: types.Duration(30 * time.Second),
+	}
+
+	node, err := EncodeToNode(element, DefaultRootName, EncoderToNodeOpts{TagName: TagLabel})
+	require.NoError(t, err)
+
+	err = AddMetadata(element, node, MetadataOpts{TagName: TagLabel})
+	require.NoError(t, err)
+
+	flats, err := EncodeToFlat(element, node, FlatOpts{Separator: ".", SkipRoot: true, TagName: TagLabel})
+	require.NoError(t, err)
+
+	expected := []Flat{
+		{Name: "delays", Description: "delays description"},
+		{Name: "interval", Description: "interval description", Default: "30"},
+		{Name: "size", Description: "size description", Default: "2MB"},
+		{Name: "sizes", Description: "sizes description"},
+		{Name: "timeout", Description: "timeout description", Default: "0s"},
+	}
+	assert.Equal(t, expected, flats)
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ApplyDefaults sets the fields of element (a pointer to a struct) to the values defined by their TagDefault tag.
+// The values are parsed as the values of a node.
+// The nested structs are browsed, but not the pointers, maps, and slices of structs:
+// their defaults are applied when they are instantiated.
+func ApplyDefaults(element interface{}) error {
+	rValue := reflect.ValueOf(element)
+	if rValue.Kind() != reflect.Pointer || rValue.IsNil() || rValue.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	return applyDefaults(rValue.Elem())
+}
+
+func applyDefaults(field reflect.Value) error {
+	for i := 0; i < field.NumField(); i++ {
+		structField := field.Type().Field(i)
+		if !IsExported(structField) {
+			continue
+		}
+
+		value, ok := structField.Tag.Lookup(TagDefault)
+		if !ok {
+			if structField.Type.Kind() == reflect.Struct && !IsCustomType(structField.Type) {
+				if err := applyDefaults(field.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if !isDefaultSupported(structField.Type) {
+			return fmt.Errorf("%s: default value not supported (type %s)", structField.Name, structField.Type)
+		}
+
+		node := &Node{Name: structField.Name, FieldName: structField.Name, Value: value, Kind: structField.Type.Kind()}
+
+		err := newFiller(FillerOpts{}).fill(field.Field(i), node, structField.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isDefaultSupported reports whether a default value can be defined for typ, i.e. typ is a single value or a slice of single values.
+func isDefaultSupported(typ reflect.Type) bool {
+	if IsCustomType(typ) {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		return isDefaultSupported(typ.Elem())
+	case reflect.Slice:
+		return typ.Elem().Kind() != reflect.Slice && isDefaultSupported(typ.Elem())
+	case reflect.Struct, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan, reflect.Array:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/types"
+)
+
+type DefaultedServer struct {
+	Address string         `default:":80"`
+	Weight  *int           `default:"1"`
+	Timeout types.Duration `default:"30"`
+	Delay   time.Duration  `default:"1.5s"`
+	Methods []string       `default:"GET,POST"`
+	Enabled bool           `default:"true"`
+	Level   LogLevel       `default:"warn"`
+	Health  DefaultedHealth
+	Next    *DefaultedHealth
+	Name    string
+}
+
+type DefaultedHealth struct {
+	Path string `default:"/health"`
+}
+
+type InitializedServer struct {
+	Address string `default:":80"`
+	Port    int    `default:"80"`
+}
+
+func (s *InitializedServer) SetDefaults() {
+	s.Address = ":8080"
+}
+
+func TestApplyDefaults(t *testing.T) {
+	element := &DefaultedServer{}
+
+	err := ApplyDefaults(element)
+	require.NoError(t, err)
+
+	weight := 1
+	expected := &DefaultedServer{
+		Address: ":80",
+		Weight:  &weight,
+		Timeout: types.Duration(30 * time.Second),
+		Delay:   1500 * time.Millisecond,
+		Methods: []string{"GET", "POST"},
+		Enabled: true,
+		Level:   LogLevelWarn,
+		Health:  DefaultedHealth{Path: "/health"},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestApplyDefaults_errors(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		element interface{}
+	}{
+		{
+			desc: "invalid value",
+			element: &struct {
+				Foo int `default:"foo"`
+			}{},
+		},
+		{
+			desc: "unsupported type",
+			element: &struct {
+				Foo map[string]string `default:"foo"`
+			}{},
+		},
+		{
+			desc: "unsupported nested type",
+			element: &struct {
+				Foo struct {
+					Bar struct{ Field string } `default:"foo"`
+				}
+			}{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := ApplyDefaults(test.element)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDecode_defaults(t *testing.T) {
+	labels := map[string]string{
+		"traefik.server.name":          "foo",
+		"traefik.server.methods":       "GET",
+		"traefik.servers.bar.address":  ":443",
+		"traefik.initialized.port":     "8000",
+		"traefik.initializeds[0].port": "8001",
+	}
+
+	element := &struct {
+		Server       *DefaultedServer
+		Servers      map[string]DefaultedServer
+		Initialized  *InitializedServer
+		Initializeds []InitializedServer
+		Root         string `default:"root"`
+	}{}
+
+	err := Decode(labels, element, DefaultRootName)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", element.Server.Name)
+	assert.Equal(t, ":80", element.Server.Address)
+	assert.Equal(t, []string{"GET"}, element.Server.Methods)
+	assert.Equal(t, "/health", element.Server.Health.Path)
+	assert.Nil(t, element.Server.Next)
+
+	assert.Equal(t, ":443", element.Servers["bar"].Address)
+	assert.Equal(t, types.Duration(30*time.Second), element.Servers["bar"].Timeout)
+
+	// SetDefaults takes precedence over the default tags.
+	assert.Equal(t, &InitializedServer{Address: ":8080", Port: 8000}, element.Initialized)
+	assert.Equal(t, []InitializedServer{{Address: ":8080", Port: 8001}}, element.Initializeds)
+
+	// the root element is not instantiated by the decoding.
+	assert.Empty(t, element.Root)
+}
+
+func TestDecode_defaultsError(t *testing.T) {
+	element := &struct {
+		Server *struct {
+			Port int `default:"foo"`
+			Name string
+		}
+	}{}
+
+	err := Decode(map[string]string{"traefik.server.name": "foo"}, element, DefaultRootName)
+	require.Error(t, err)
+}
+
+func TestEncodeToFlat_defaults(t *testing.T) {
+	element := &struct {
+		Foo string `description:"foo description" default:"foo"`
+		Bar int    `description:"bar description" default:"42"`
+		Baz string `description:"baz description" default:"baz"`
+	}{
+		Baz: "custom",
+	}
+
+	node, err := EncodeToNode(element, DefaultRootName, EncoderToNodeOpts{TagName: TagLabel})
+	require.NoError(t, err)
+
+	err = AddMetadata(element, node, MetadataOpts{TagName: TagLabel})
+	require.NoError(t, err)
+
+	flats, err := EncodeToFlat(element, node, FlatOpts{Separator: ".", SkipRoot: true, TagName: TagLabel})
+	require.NoError(t, err)
+
+	expected := []Flat{
+		{Name: "bar", Description: "bar description", Default: "42"},
+		{Name: "baz", Description: "baz description", Default: "custom"},
+		{Name: "foo", Description: "foo description", Default: "foo"},
+	}
+	assert.Equal(t, expected, flats)
+}
@@ -0,0 +1,241 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeType is the type of change of a configuration value.
+type ChangeType string
+
+// Change types.
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// Change is a change of a configuration value between two configurations.
+// The sensitive values (see TagSensitive) are replaced by RedactedValue.
+type Change struct {
+	Type     ChangeType `json:"type"`
+	Path     string     `json:"path"`
+	OldValue string     `json:"oldValue,omitempty"`
+	NewValue string     `json:"newValue,omitempty"`
+}
+
+func (c Change) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s: %q", c.Path, c.NewValue)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s: %q", c.Path, c.OldValue)
+	default:
+		return fmt.Sprintf("~ %s: %q -> %q", c.Path, c.OldValue, c.NewValue)
+	}
+}
+
+// Diff returns the changes of the values between the old and the new configurations, sorted by path.
+// The operation goes through two stages roughly summarized as:
+// - typed configurations -> trees of nodes augmented with metadata
+// - trees of nodes -> changes of the leaf values (see DiffNodes).
+func Diff(oldElement, newElement interface{}) ([]Change, error) {
+	oldNode, err := encodeToDiffNode(oldElement)
+	if err != nil {
+		return nil, err
+	}
+
+	newNode, err := encodeToDiffNode(newElement)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffNodes(oldNode, newNode), nil
+}
+
+func encodeToDiffNode(element interface{}) (*Node, error) {
+	if element == nil {
+		return nil, nil
+	}
+
+	rValue := reflect.ValueOf(element)
+	if rValue.Kind() == reflect.Pointer && rValue.IsNil() {
+		return nil, nil
+	}
+
+	// the slices are not encoded as structs, their elements are compared by index.
+	etnOpts := EncoderToNodeOpts{OmitEmpty: false, TagName: TagLabel, AllowSliceAsStruct: false}
+	node, err := EncodeToNode(element, DefaultRootName, etnOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// an element without values (ex: only nil pointers and empty maps) is an empty tree.
+	if len(node.Children) == 0 {
+		return nil, nil
+	}
+
+	metaOpts := MetadataOpts{TagName: TagLabel, AllowSliceAsStruct: false}
+	err = AddMetadata(element, node, metaOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = setSliceElements(node, rValue); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// setSliceElements replaces the values of the slices of single values (joined by EncodeToNode) by the lists of their elements,
+// to compare the slices element by element.
+func setSliceElements(node *Node, rValue reflect.Value) error {
+	for rValue.Kind() == reflect.Pointer || rValue.Kind() == reflect.Interface {
+		if rValue.IsNil() {
+			return nil
+		}
+		rValue = rValue.Elem()
+	}
+
+	if !rValue.IsValid() || node.RawValue != nil || IsCustomType(rValue.Type()) {
+		return nil
+	}
+
+	switch rValue.Kind() {
+	case reflect.Struct:
+		for _, child := range node.Children {
+			if err := setSliceElements(child, rValue.FieldByName(child.FieldName)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		for _, child := range node.Children {
+			key := reflect.ValueOf(child.FieldName)
+			if !key.Type().ConvertibleTo(rValue.Type().Key()) {
+				continue
+			}
+
+			if err := setSliceElements(child, rValue.MapIndex(key.Convert(rValue.Type().Key()))); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		if len(node.Children) > 0 {
+			for i, child := range node.Children {
+				if i < rValue.Len() {
+					if err := setSliceElements(child, rValue.Index(i)); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+
+		elements := make([]interface{}, 0, rValue.Len())
+		for i := 0; i < rValue.Len(); i++ {
+			element := &Node{}
+			if err := (encoderToNode{}).setNodeValue(element, rValue.Index(i)); err != nil {
+				return err
+			}
+
+			elements = append(elements, element.Value)
+		}
+
+		node.Value = ""
+		node.RawValue = elements
+	}
+
+	return nil
+}
+
+// DiffNodes returns the changes of the leaf values between the old and the new trees of nodes, sorted by path.
+// The paths are relative to the roots, the map entries and the slice elements are identified by their key and index
+// (ex: servers[0].url, routes.foo.rule).
+// The slices of single values are compared as a whole, unless their elements are listed in raw values.
+// The sensitive values (see TagSensitive) are compared, but they are reported as RedactedValue.
+func DiffNodes(oldNode, newNode *Node) []Change {
+	oldValues := make(map[string]diffValue)
+	flattenNode(oldValues, oldNode, "", false)
+
+	newValues := make(map[string]diffValue)
+	flattenNode(newValues, newNode, "", false)
+
+	var changes []Change
+
+	for path, oldValue := range oldValues {
+		newValue, ok := newValues[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Type: ChangeRemoved, Path: path, OldValue: oldValue.String()})
+		case oldValue.value != newValue.value:
+			changes = append(changes, Change{Type: ChangeModified, Path: path, OldValue: oldValue.String(), NewValue: newValue.String()})
+		}
+	}
+
+	for path, newValue := range newValues {
+		if _, ok := oldValues[path]; !ok {
+			changes = append(changes, Change{Type: ChangeAdded, Path: path, NewValue: newValue.String()})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+type diffValue struct {
+	value     string
+	sensitive bool
+}
+
+func (v diffValue) String() string {
+	if v.sensitive {
+		return RedactedValue
+	}
+
+	return v.value
+}
+
+// flattenNode collects the leaf values of the node located at path.
+func flattenNode(values map[string]diffValue, node *Node, path string, sensitive bool) {
+	if node == nil || node.Disabled {
+		return
+	}
+
+	sensitive = sensitive || node.Sensitive || IsSensitive(node.Tag)
+
+	if node.RawValue != nil {
+		flattenRawValue(values, node.RawValue, path, sensitive)
+		return
+	}
+
+	if len(node.Children) > 0 {
+		for _, child := range node.Children {
+			flattenNode(values, child, childPath(path, child.Name), sensitive)
+		}
+		return
+	}
+
+	values[path] = diffValue{value: node.Value, sensitive: sensitive}
+}
+
+func flattenRawValue(values map[string]diffValue, rawValue interface{}, path string, sensitive bool) {
+	switch value := rawValue.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			flattenRawValue(values, v, childPath(path, k), sensitive)
+		}
+	case []interface{}:
+		for i, v := range value {
+			flattenRawValue(values, v, fmt.Sprintf("%s[%d]", path, i), sensitive)
+		}
+	default:
+		values[path] = diffValue{value: fmt.Sprint(value), sensitive: sensitive}
+	}
+}
@@ -0,0 +1,303 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type DiffConfig struct {
+	Name     string
+	Port     int
+	Names    []string
+	Servers  []DiffServer
+	Routes   map[string]*DiffRoute
+	Password string      `sensitive:"true"`
+	Auth     *DiffAuth   `sensitive:"true"`
+	Health   *DiffHealth `label:"allowEmpty"`
+	Internal string      `label:"-"`
+}
+
+type DiffSliceAsStruct struct {
+	Servers []DiffServer `label-slice-as-struct:"server"`
+}
+
+type DiffOptional struct {
+	Server *DiffServer
+	Routes map[string]*DiffRoute
+}
+
+type DiffServer struct {
+	URL string
+}
+
+type DiffRoute struct {
+	Rule string
+}
+
+type DiffAuth struct {
+	User  string
+	Token string
+}
+
+type DiffHealth struct {
+	Path string
+}
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		old      *DiffConfig
+		new      *DiffConfig
+		expected []Change
+	}{
+		{
+			desc: "no change",
+			old:  &DiffConfig{Name: "foo", Port: 80},
+			new:  &DiffConfig{Name: "foo", Port: 80},
+		},
+		{
+			desc: "modified values",
+			old:  &DiffConfig{Name: "foo", Port: 80},
+			new:  &DiffConfig{Name: "bar", Port: 8080},
+			expected: []Change{
+				{Type: ChangeModified, Path: "Name", OldValue: "foo", NewValue: "bar"},
+				{Type: ChangeModified, Path: "Port", OldValue: "80", NewValue: "8080"},
+			},
+		},
+		{
+			desc: "slice of values",
+			old:  &DiffConfig{Names: []string{"a", "b", "c"}},
+			new:  &DiffConfig{Names: []string{"a", "d"}},
+			expected: []Change{
+				{Type: ChangeModified, Path: "Names[1]", OldValue: "b", NewValue: "d"},
+				{Type: ChangeRemoved, Path: "Names[2]", OldValue: "c"},
+			},
+		},
+		{
+			desc: "slice of values containing the separator",
+			old:  &DiffConfig{Names: []string{"a, b"}},
+			new:  &DiffConfig{Names: []string{"a, c"}},
+			expected: []Change{
+				{Type: ChangeModified, Path: "Names[0]", OldValue: "a, b", NewValue: "a, c"},
+			},
+		},
+		{
+			desc: "slice of structs",
+			old:  &DiffConfig{Servers: []DiffServer{{URL: "a"}}},
+			new:  &DiffConfig{Servers: []DiffServer{{URL: "b"}, {URL: "c"}}},
+			expected: []Change{
+				{Type: ChangeModified, Path: "Servers[0].URL", OldValue: "a", NewValue: "b"},
+				{Type: ChangeAdded, Path: "Servers[1].URL", NewValue: "c"},
+			},
+		},
+		{
+			desc: "map entries",
+			old: &DiffConfig{Routes: map[string]*DiffRoute{
+				"foo": {Rule: "a"},
+				"bar": {Rule: "b"},
+			}},
+			new: &DiffConfig{Routes: map[string]*DiffRoute{
+				"foo": {Rule: "c"},
+				"baz": {Rule: "d"},
+			}},
+			expected: []Change{
+				{Type: ChangeRemoved, Path: "Routes.bar.Rule", OldValue: "b"},
+				{Type: ChangeAdded, Path: "Routes.baz.Rule", NewValue: "d"},
+				{Type: ChangeModified, Path: "Routes.foo.Rule", OldValue: "a", NewValue: "c"},
+			},
+		},
+		{
+			desc: "empty pointer",
+			old:  &DiffConfig{},
+			new:  &DiffConfig{Health: &DiffHealth{}},
+			expected: []Change{
+				{Type: ChangeAdded, Path: "Health.Path"},
+			},
+		},
+		{
+			desc: "sensitive values",
+			old:  &DiffConfig{Password: "foo", Auth: &DiffAuth{User: "u1", Token: "t1"}},
+			new:  &DiffConfig{Password: "bar", Auth: &DiffAuth{User: "u1"}},
+			expected: []Change{
+				{Type: ChangeModified, Path: "Auth.Token", OldValue: RedactedValue, NewValue: RedactedValue},
+				{Type: ChangeModified, Path: "Password", OldValue: RedactedValue, NewValue: RedactedValue},
+			},
+		},
+		{
+			desc: "ignored field",
+			old:  &DiffConfig{Internal: "foo"},
+			new:  &DiffConfig{Internal: "bar"},
+		},
+		{
+			desc: "nil old configuration",
+			new:  &DiffConfig{Name: "foo"},
+			expected: []Change{
+				{Type: ChangeAdded, Path: "Name", NewValue: "foo"},
+				{Type: ChangeAdded, Path: "Password", NewValue: RedactedValue},
+				{Type: ChangeAdded, Path: "Port", NewValue: "0"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			changes, err := Diff(test.old, test.new)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, changes)
+		})
+	}
+}
+
+func TestDiff_structs(t *testing.T) {
+	changes, err := Diff(DiffConfig{Name: "foo"}, DiffConfig{Name: "bar"})
+	require.NoError(t, err)
+
+	expected := []Change{
+		{Type: ChangeModified, Path: "Name", OldValue: "foo", NewValue: "bar"},
+	}
+	assert.Equal(t, expected, changes)
+}
+
+func TestDiff_sliceAsStruct(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		old      *DiffSliceAsStruct
+		new      *DiffSliceAsStruct
+		expected []Change
+	}{
+		{
+			desc: "several elements",
+			old:  &DiffSliceAsStruct{Servers: []DiffServer{{URL: "a"}, {URL: "b"}}},
+			new:  &DiffSliceAsStruct{Servers: []DiffServer{{URL: "a"}, {URL: "c"}, {URL: "d"}}},
+			expected: []Change{
+				{Type: ChangeModified, Path: "Servers[1].URL", OldValue: "b", NewValue: "c"},
+				{Type: ChangeAdded, Path: "Servers[2].URL", NewValue: "d"},
+			},
+		},
+		{
+			desc: "empty slice",
+			old:  &DiffSliceAsStruct{Servers: []DiffServer{}},
+			new:  &DiffSliceAsStruct{Servers: []DiffServer{{URL: "a"}}},
+			expected: []Change{
+				{Type: ChangeAdded, Path: "Servers[0].URL", NewValue: "a"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			changes, err := Diff(test.old, test.new)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, changes)
+		})
+	}
+}
+
+func TestDiff_emptyConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		old      *DiffOptional
+		new      *DiffOptional
+		expected []Change
+	}{
+		{
+			desc: "from empty",
+			old:  &DiffOptional{},
+			new:  &DiffOptional{Server: &DiffServer{URL: "a"}, Routes: map[string]*DiffRoute{"foo": {Rule: "b"}}},
+			expected: []Change{
+				{Type: ChangeAdded, Path: "Routes.foo.Rule", NewValue: "b"},
+				{Type: ChangeAdded, Path: "Server.URL", NewValue: "a"},
+			},
+		},
+		{
+			desc: "to empty",
+			old:  &DiffOptional{Server: &DiffServer{URL: "a"}},
+			new:  &DiffOptional{Routes: map[string]*DiffRoute{}},
+			expected: []Change{
+				{Type: ChangeRemoved, Path: "Server.URL", OldValue: "a"},
+			},
+		},
+		{
+			desc: "both empty",
+			old:  &DiffOptional{},
+			new:  &DiffOptional{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			changes, err := Diff(test.old, test.new)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, changes)
+		})
+	}
+}
+
+func TestDiffNodes_rawValue(t *testing.T) {
+	oldNode := &Node{
+		Name: "traefik",
+		Children: []*Node{
+			{Name: "raw", RawValue: map[string]interface{}{"foo": "a", "bar": []interface{}{1, 2}}},
+		},
+	}
+
+	newNode := &Node{
+		Name: "traefik",
+		Children: []*Node{
+			{Name: "raw", RawValue: map[string]interface{}{"foo": "b", "bar": []interface{}{1}}},
+		},
+	}
+
+	expected := []Change{
+		{Type: ChangeRemoved, Path: "raw.bar[1]", OldValue: "2"},
+		{Type: ChangeModified, Path: "raw.foo", OldValue: "a", NewValue: "b"},
+	}
+	assert.Equal(t, expected, DiffNodes(oldNode, newNode))
+}
+
+func TestDiffNodes_sensitive(t *testing.T) {
+	oldNode := &Node{
+		Name: "traefik",
+		Children: []*Node{
+			{Name: "password", Value: "a", Sensitive: true},
+			{Name: "token", Value: "x", Sensitive: true},
+			{Name: "raw", RawValue: map[string]interface{}{"foo": "a"}, Sensitive: true},
+		},
+	}
+
+	newNode := &Node{
+		Name: "traefik",
+		Children: []*Node{
+			{Name: "password", Value: "b", Sensitive: true},
+			{Name: "token", Value: "x", Sensitive: true},
+			{Name: "raw", RawValue: map[string]interface{}{"foo": "b"}, Sensitive: true},
+		},
+	}
+
+	// the raw values are compared, and redacted when reported.
+	expected := []Change{
+		{Type: ChangeModified, Path: "password", OldValue: RedactedValue, NewValue: RedactedValue},
+		{Type: ChangeModified, Path: "raw.foo", OldValue: RedactedValue, NewValue: RedactedValue},
+	}
+	assert.Equal(t, expected, DiffNodes(oldNode, newNode))
+}
+
+func TestChange_String(t *testing.T) {
+	assert.Equal(t, `+ foo: "a"`, Change{Type: ChangeAdded, Path: "foo", NewValue: "a"}.String())
+	assert.Equal(t, `- foo: "a"`, Change{Type: ChangeRemoved, Path: "foo", OldValue: "a"}.String())
+	assert.Equal(t, `~ foo: "a" -> "b"`, Change{Type: ChangeModified, Path: "foo", OldValue: "a", NewValue: "b"}.String())
+}
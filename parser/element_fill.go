@@ -6,9 +6,6 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
-	"time"
-
-	"github.com/traefik/paerser/types"
 )
 
 const defaultRawSliceSeparator = ","
@@ -21,6 +18,17 @@ type initializer interface {
 type FillerOpts struct {
 	AllowSliceAsStruct bool
 	RawSliceSeparator  string
+	// AggregateErrors allows to fill all the valid fields and to return all the errors (DecodeErrors),
+	// instead of stopping at the first error.
+	AggregateErrors bool
+	// Interpolate enables the expansion of the references to the environment variables in the values (see Interpolate),
+	// before filling the element.
+	Interpolate bool
+	// LookupEnv retrieves the value of the interpolated environment variables (os.LookupEnv if nil).
+	LookupEnv func(string) (string, bool)
+	// Resolve enables the resolution of the references to the secrets in the values (see RegisterResolver),
+	// before filling the element.
+	Resolve bool
 }
 
 // Fill populates the fields of the element using the information in node.
@@ -55,15 +63,61 @@ func (f filler) Fill(element interface{}, node *Node) error {
 		return fmt.Errorf("struct are not supported, use pointer instead")
 	}
 
-	return f.fill(root.Elem(), node)
+	if f.Interpolate || f.Resolve {
+		expanded, err := f.expand(node)
+		if err != nil {
+			return err
+		}
+
+		node = expanded
+	}
+
+	return redactSensitiveErrors(f.fill(root.Elem(), node, node.Name), node)
 }
 
-func (f filler) fill(field reflect.Value, node *Node) error {
+// expand returns a copy of the node, the references being expanded in the values (see Interpolate and RegisterResolver).
+// The values of the given node are not modified,
+// but its nodes including a resolved reference are marked as sensitive (see Node.SensitivePaths).
+// When the errors are aggregated, the copy is returned with the errors, the invalid values being kept as is.
+func (f filler) expand(node *Node) (*Node, error) {
+	clone := cloneNode(node)
+
+	x := expander{env: f.Interpolate, lookupEnv: f.LookupEnv, resolve: f.Resolve}
+	err := f.expandNode(x, clone, clone.Name)
+
+	copySensitive(node, clone)
+
+	if err != nil {
+		return clone, redactSensitiveErrors(err, clone)
+	}
+
+	return clone, nil
+}
+
+// copySensitive marks the nodes of the tree as sensitive like the related nodes of its copy.
+func copySensitive(node, clone *Node) {
+	node.Sensitive = node.Sensitive || clone.Sensitive
+
+	for i, child := range node.Children {
+		copySensitive(child, clone.Children[i])
+	}
+}
+
+// fill populates the field using the information in the node located at path.
+func (f filler) fill(field reflect.Value, node *Node, path string) error {
+	return wrapDecodeError(f.fillValue(field, node, path), path, node, field.Type())
+}
+
+func (f filler) fillValue(field reflect.Value, node *Node, path string) error {
 	// related to allow-empty or ignore tag
 	if node.Disabled {
 		return nil
 	}
 
+	if IsCustomType(field.Type()) {
+		return setCustom(field, node.Value)
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(node.Value)
@@ -96,22 +150,26 @@ func (f filler) fill(field reflect.Value, node *Node) error {
 	case reflect.Float64:
 		return setFloat(field, node.Value, 64)
 	case reflect.Struct:
-		return f.setStruct(field, node)
+		return f.setStruct(field, node, path)
 	case reflect.Pointer:
-		return f.setPtr(field, node)
+		return f.setPtr(field, node, path)
 	case reflect.Map:
-		return f.setMap(field, node)
+		return f.setMap(field, node, path)
 	case reflect.Slice:
-		return f.setSlice(field, node)
+		return f.setSlice(field, node, path)
 	default:
 		return nil
 	}
 }
 
-func (f filler) setPtr(field reflect.Value, node *Node) error {
+func (f filler) setPtr(field reflect.Value, node *Node, path string) error {
 	if field.IsNil() {
 		field.Set(reflect.New(field.Type().Elem()))
 
+		if err := ApplyDefaults(field.Interface()); err != nil {
+			return err
+		}
+
 		if field.Type().Implements(reflect.TypeOf((*initializer)(nil)).Elem()) {
 			method := field.MethodByName("SetDefaults")
 			if method.IsValid() {
@@ -120,31 +178,50 @@ func (f filler) setPtr(field reflect.Value, node *Node) error {
 		}
 	}
 
-	return f.fill(field.Elem(), node)
+	return f.fill(field.Elem(), node, path)
 }
 
-func (f filler) setStruct(field reflect.Value, node *Node) error {
+func (f filler) setStruct(field reflect.Value, node *Node, path string) error {
+	var errs DecodeErrors
+
 	for _, child := range node.Children {
+		p := childPath(path, child.Name)
+
 		fd := field.FieldByName(child.FieldName)
 
 		zeroValue := reflect.Value{}
 		if fd == zeroValue {
-			return fmt.Errorf("field not found, node: %s (%s)", child.Name, child.FieldName)
+			err := &DecodeError{
+				Path:   p,
+				Value:  child.Value,
+				Source: child.Source,
+				Err:    fmt.Errorf("field not found, node: %s (%s)", child.Name, child.FieldName),
+			}
+			if !f.AggregateErrors {
+				return err
+			}
+
+			errs = append(errs, err)
+			continue
 		}
 
-		err := f.fill(fd, child)
+		err := f.fill(fd, child, p)
 		if err != nil {
-			return err
+			if !f.AggregateErrors {
+				return err
+			}
+
+			errs = append(errs, AsDecodeErrors(err)...)
 		}
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
-func (f filler) setSlice(field reflect.Value, node *Node) error {
-	if field.Type().Elem().Kind() == reflect.Struct ||
-		field.Type().Elem().Kind() == reflect.Pointer && field.Type().Elem().Elem().Kind() == reflect.Struct {
-		return f.setSliceStruct(field, node)
+func (f filler) setSlice(field reflect.Value, node *Node, path string) error {
+	if !IsCustomType(field.Type().Elem()) && (field.Type().Elem().Kind() == reflect.Struct ||
+		field.Type().Elem().Kind() == reflect.Pointer && field.Type().Elem().Elem().Kind() == reflect.Struct) {
+		return f.setSliceStruct(field, node, path)
 	}
 
 	if len(node.Value) == 0 {
@@ -172,6 +249,14 @@ func makeSlice(field reflect.Value, values []string) error {
 	for i := 0; i < len(values); i++ {
 		value := strings.TrimSpace(values[i])
 
+		if IsCustomType(field.Type().Elem()) {
+			err := setCustom(field.Index(i), value)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		switch field.Type().Elem().Kind() {
 		case reflect.String:
 			field.Index(i).SetString(value)
@@ -250,35 +335,47 @@ func makeSlice(field reflect.Value, values []string) error {
 	return nil
 }
 
-func (f filler) setSliceStruct(field reflect.Value, node *Node) error {
-	if f.AllowSliceAsStruct && node.Tag.Get(TagLabelSliceAsStruct) != "" {
-		return f.setSliceAsStruct(field, node)
+func (f filler) setSliceStruct(field reflect.Value, node *Node, path string) error {
+	// the lists of indexed elements (ex: from a file, merged with other sources) are not filled as a struct.
+	if f.AllowSliceAsStruct && node.Tag.Get(TagLabelSliceAsStruct) != "" && !hasIndexedChildren(node) {
+		return f.setSliceAsStruct(field, node, path)
 	}
 
 	field.Set(reflect.MakeSlice(field.Type(), len(node.Children), len(node.Children)))
 
+	var errs DecodeErrors
+
 	for i, child := range node.Children {
 		// use Ptr to allow "SetDefaults"
 		value := reflect.New(reflect.PointerTo(field.Type().Elem()))
-		err := f.setPtr(value, child)
+		err := f.setPtr(value, child, childPath(path, child.Name))
 		if err != nil {
-			return err
+			if !f.AggregateErrors {
+				return err
+			}
+
+			errs = append(errs, AsDecodeErrors(err)...)
 		}
 
 		field.Index(i).Set(value.Elem().Elem())
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
-func (f filler) setSliceAsStruct(field reflect.Value, node *Node) error {
+// hasIndexedChildren reports whether the children of the node are the indexed elements of a slice (ex: [0]).
+func hasIndexedChildren(node *Node) bool {
+	return len(node.Children) > 0 && strings.HasPrefix(node.Children[0].Name, "[")
+}
+
+func (f filler) setSliceAsStruct(field reflect.Value, node *Node, path string) error {
 	if len(node.Children) == 0 {
 		return fmt.Errorf("invalid slice: node %s", node.Name)
 	}
 
 	// use Ptr to allow "SetDefaults"
 	value := reflect.New(reflect.PointerTo(field.Type().Elem()))
-	if err := f.setPtr(value, node); err != nil {
+	if err := f.setPtr(value, node, path); err != nil {
 		return err
 	}
 
@@ -290,7 +387,7 @@ func (f filler) setSliceAsStruct(field reflect.Value, node *Node) error {
 	return nil
 }
 
-func (f filler) setMap(field reflect.Value, node *Node) error {
+func (f filler) setMap(field reflect.Value, node *Node, path string) error {
 	if field.IsNil() {
 		field.Set(reflect.MakeMap(field.Type()))
 	}
@@ -311,12 +408,26 @@ func (f filler) setMap(field reflect.Value, node *Node) error {
 		return nil
 	}
 
+	var errs DecodeErrors
+
 	for _, child := range node.Children {
+		p := childPath(path, child.Name)
+
 		ptrValue := reflect.New(reflect.PointerTo(field.Type().Elem()))
 
-		err := f.fill(ptrValue, child)
+		err := f.fill(ptrValue, child, p)
 		if err != nil {
-			return err
+			if !f.AggregateErrors {
+				return err
+			}
+
+			dErrs := AsDecodeErrors(err)
+			errs = append(errs, dErrs...)
+
+			// the entry itself is invalid, only the entries with some valid fields are kept.
+			if hasDecodeError(dErrs, p) {
+				continue
+			}
 		}
 
 		value := ptrValue.Elem().Elem()
@@ -325,39 +436,16 @@ func (f filler) setMap(field reflect.Value, node *Node) error {
 		field.SetMapIndex(key, value)
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
 func setInt(field reflect.Value, value string, bitSize int) error {
-	switch field.Type() {
-	case reflect.TypeOf(types.Duration(0)):
-		return setDuration(field, value, bitSize, time.Second)
-	case reflect.TypeOf(time.Duration(0)):
-		return setDuration(field, value, bitSize, time.Nanosecond)
-	default:
-		val, err := strconv.ParseInt(value, 10, bitSize)
-		if err != nil {
-			return err
-		}
-
-		field.Set(reflect.ValueOf(val).Convert(field.Type()))
-		return nil
-	}
-}
-
-func setDuration(field reflect.Value, value string, bitSize int, defaultUnit time.Duration) error {
 	val, err := strconv.ParseInt(value, 10, bitSize)
-	if err == nil {
-		field.Set(reflect.ValueOf(time.Duration(val) * defaultUnit).Convert(field.Type()))
-		return nil
-	}
-
-	duration, err := time.ParseDuration(value)
 	if err != nil {
 		return err
 	}
 
-	field.Set(reflect.ValueOf(duration).Convert(field.Type()))
+	field.Set(reflect.ValueOf(val).Convert(field.Type()))
 	return nil
 }
 
@@ -1,6 +1,10 @@
 package parser
 
 import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
 	"reflect"
 	"testing"
 	"time"
@@ -414,6 +418,98 @@ func TestFill(t *testing.T) {
 			element:  &struct{ Foo types.Duration }{},
 			expected: expected{element: &struct{ Foo types.Duration }{Foo: types.Duration(4 * time.Second)}},
 		},
+		{
+			desc: "TextUnmarshaler with kind string",
+			node: &Node{
+				Name: "traefik",
+				Kind: reflect.Struct,
+				Children: []*Node{
+					{Name: "Foo", FieldName: "Foo", Value: "debug", Kind: reflect.Int},
+				},
+			},
+			element:  &struct{ Foo LogLevel }{},
+			expected: expected{element: &struct{ Foo LogLevel }{Foo: LogLevelDebug}},
+		},
+		{
+			desc: "invalid TextUnmarshaler value",
+			node: &Node{
+				Name: "traefik",
+				Kind: reflect.Struct,
+				Children: []*Node{
+					{Name: "Foo", FieldName: "Foo", Value: "foo", Kind: reflect.Int},
+				},
+			},
+			element:  &struct{ Foo LogLevel }{},
+			expected: expected{error: true},
+		},
+		{
+			desc: "TextUnmarshaler with kind slice",
+			node: &Node{
+				Name: "traefik",
+				Kind: reflect.Struct,
+				Children: []*Node{
+					{Name: "Foo", FieldName: "Foo", Value: "10.0.0.1", Kind: reflect.Slice},
+				},
+			},
+			element:  &struct{ Foo net.IP }{},
+			expected: expected{element: &struct{ Foo net.IP }{Foo: net.ParseIP("10.0.0.1")}},
+		},
+		{
+			desc: "TextUnmarshaler with kind struct",
+			node: &Node{
+				Name: "traefik",
+				Kind: reflect.Struct,
+				Children: []*Node{
+					{Name: "Foo", FieldName: "Foo", Value: "10.0.0.0/8", Kind: reflect.Struct},
+				},
+			},
+			element:  &struct{ Foo netip.Prefix }{},
+			expected: expected{element: &struct{ Foo netip.Prefix }{Foo: netip.MustParsePrefix("10.0.0.0/8")}},
+		},
+		{
+			desc: "pointer of TextUnmarshaler",
+			node: &Node{
+				Name: "traefik",
+				Kind: reflect.Struct,
+				Children: []*Node{
+					{Name: "Foo", FieldName: "Foo", Value: "123456789012345678901234567890", Kind: reflect.Pointer},
+				},
+			},
+			element: &struct{ Foo *big.Int }{},
+			expected: expected{element: &struct{ Foo *big.Int }{Foo: func() *big.Int {
+				i, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+				return i
+			}()}},
+		},
+		{
+			desc: "slice of TextUnmarshaler",
+			node: &Node{
+				Name: "traefik",
+				Kind: reflect.Struct,
+				Children: []*Node{
+					{Name: "Foo", FieldName: "Foo", Value: "10.0.0.0/8, 192.168.0.0/16", Kind: reflect.Slice},
+				},
+			},
+			element: &struct{ Foo []netip.Prefix }{},
+			expected: expected{element: &struct{ Foo []netip.Prefix }{Foo: []netip.Prefix{
+				netip.MustParsePrefix("10.0.0.0/8"),
+				netip.MustParsePrefix("192.168.0.0/16"),
+			}}},
+		},
+		{
+			desc: "map of TextUnmarshaler",
+			node: &Node{
+				Name: "traefik",
+				Kind: reflect.Struct,
+				Children: []*Node{
+					{Name: "Foo", FieldName: "Foo", Kind: reflect.Map, Children: []*Node{
+						{Name: "bar", Value: "warn", Kind: reflect.Int},
+					}},
+				},
+			},
+			element:  &struct{ Foo map[string]LogLevel }{},
+			expected: expected{element: &struct{ Foo map[string]LogLevel }{Foo: map[string]LogLevel{"bar": LogLevelWarn}}},
+		},
 		{
 			desc: "bool",
 			node: &Node{
@@ -1646,3 +1742,39 @@ type FiiFoo struct {
 	Fii string
 	Fuu Bouya
 }
+
+type LogLevel int
+
+const (
+	LogLevelInfo LogLevel = iota
+	LogLevelDebug
+	LogLevelWarn
+)
+
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "info":
+		*l = LogLevelInfo
+	case "debug":
+		*l = LogLevelDebug
+	case "warn":
+		*l = LogLevelWarn
+	default:
+		return fmt.Errorf("unknown log level: %s", text)
+	}
+
+	return nil
+}
+
+func (l LogLevel) MarshalText() ([]byte, error) {
+	switch l {
+	case LogLevelInfo:
+		return []byte("info"), nil
+	case LogLevelDebug:
+		return []byte("debug"), nil
+	case LogLevelWarn:
+		return []byte("warn"), nil
+	default:
+		return nil, fmt.Errorf("unknown log level: %d", l)
+	}
+}
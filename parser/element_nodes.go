@@ -12,6 +12,11 @@ type EncoderToNodeOpts struct {
 	TagName            string
 	OmitEmpty          bool
 	AllowSliceAsStruct bool
+	// Redact replaces the sensitive values (see TagSensitive) by RedactedValue.
+	Redact bool
+	// SensitivePaths are the paths, relative to the root node (see Node.Get), of the values replaced by RedactedValue,
+	// whatever Redact (ex: the paths of the resolved secrets of a decoded tree, see Node.SensitivePaths).
+	SensitivePaths []string
 }
 
 // EncodeToNode converts an element to a node.
@@ -27,14 +32,92 @@ func EncodeToNode(element interface{}, rootName string, opts EncoderToNodeOpts)
 		return nil, err
 	}
 
+	for _, path := range opts.SensitivePaths {
+		if child := getSensitiveNode(node, path); child != nil {
+			child.Sensitive = true
+			redactSensitiveNode(child)
+		}
+	}
+
 	return node, nil
 }
 
+// getSensitiveNode returns the descendant node located at path (see Node.Get),
+// or the node holding the raw value including path (ex: a map[string]interface{}).
+func getSensitiveNode(node *Node, path string) *Node {
+	segments, err := splitPath(path)
+	if err != nil {
+		return nil
+	}
+
+	for _, segment := range segments {
+		if node.RawValue != nil {
+			return node
+		}
+
+		if node = containsNode(node.Children, segment); node == nil {
+			return nil
+		}
+	}
+
+	return node
+}
+
+// redactSensitiveNode replaces the values of the node and its children by RedactedValue.
+func redactSensitiveNode(node *Node) {
+	if node.Value != "" {
+		node.Value = RedactedValue
+	}
+
+	if node.RawValue != nil {
+		node.RawValue = redactSensitiveRawValue(node.RawValue)
+	}
+
+	for _, child := range node.Children {
+		redactSensitiveNode(child)
+	}
+}
+
+// redactSensitiveRawValue returns a copy of the raw value, the leaf values replaced by RedactedValue.
+func redactSensitiveRawValue(raw interface{}) interface{} {
+	switch v := raw.(type) {
+	case nil:
+		return nil
+
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = redactSensitiveRawValue(value)
+		}
+		return m
+
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, value := range v {
+			s[i] = redactSensitiveRawValue(value)
+		}
+		return s
+
+	default:
+		return RedactedValue
+	}
+}
+
 type encoderToNode struct {
 	EncoderToNodeOpts
 }
 
 func (e encoderToNode) setNodeValue(node *Node, rValue reflect.Value) error {
+	if rValue.IsValid() && isCustomMarshaler(rValue.Type()) {
+		value, err := getCustomValue(rValue)
+		if err != nil {
+			return err
+		}
+
+		node.Value = value
+		return nil
+	}
+
 	switch rValue.Kind() {
 	case reflect.String:
 		node.Value = rValue.String()
@@ -102,12 +185,20 @@ func (e encoderToNode) setStructValue(node *Node, rValue reflect.Value) error {
 			return err
 		}
 
+		if e.Redact && IsSensitive(field.Tag) {
+			redactSensitiveNode(child)
+		}
+
+		if e.OmitEmpty && IsCustomType(field.Type) && child.Value == "" {
+			continue
+		}
+
 		if field.Type.Kind() == reflect.Pointer {
 			if field.Type.Elem().Kind() != reflect.Struct && fieldValue.IsNil() {
 				continue
 			}
 
-			if field.Type.Elem().Kind() == reflect.Struct && len(child.Children) == 0 {
+			if field.Type.Elem().Kind() == reflect.Struct && !IsCustomType(field.Type.Elem()) && len(child.Children) == 0 {
 				if field.Tag.Get(e.TagName) != TagLabelAllowEmpty {
 					continue
 				}
@@ -140,6 +231,22 @@ func (e encoderToNode) setMapValue(node *Node, rValue reflect.Value) error {
 }
 
 func (e encoderToNode) setSliceValue(node *Node, rValue reflect.Value) error {
+	if isCustomMarshaler(rValue.Type().Elem()) {
+		var values []string
+
+		for i := 0; i < rValue.Len(); i++ {
+			value, err := getCustomValue(rValue.Index(i))
+			if err != nil {
+				return err
+			}
+
+			values = append(values, value)
+		}
+
+		node.Value = strings.Join(values, ", ")
+		return nil
+	}
+
 	// label-slice-as-struct
 	if rValue.Type().Elem().Kind() == reflect.Struct && !strings.EqualFold(node.Name, node.FieldName) {
 		if rValue.Len() > 1 {
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"math/big"
+	"net"
+	"net/netip"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -800,6 +803,51 @@ func TestEncodeToNode(t *testing.T) {
 				},
 			}},
 		},
+		{
+			desc: "TextMarshaler",
+			element: struct {
+				Foo LogLevel
+				Bar netip.Prefix
+				Baz *big.Int
+				Bur []netip.Prefix
+				Bir net.IP
+			}{
+				Foo: LogLevelWarn,
+				Bar: netip.MustParsePrefix("10.0.0.0/8"),
+				Baz: big.NewInt(42),
+				Bur: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")},
+				Bir: net.ParseIP("10.0.0.1"),
+			},
+			expected: expected{
+				node: &Node{Name: "traefik", Children: []*Node{
+					{Name: "Foo", FieldName: "Foo", Value: "warn"},
+					{Name: "Bar", FieldName: "Bar", Value: "10.0.0.0/8"},
+					{Name: "Baz", FieldName: "Baz", Value: "42"},
+					{Name: "Bur", FieldName: "Bur", Value: "10.0.0.0/8, 192.168.0.0/16"},
+					{Name: "Bir", FieldName: "Bir", Value: "10.0.0.1"},
+				}},
+			},
+		},
+		{
+			desc: "TextMarshaler empty values",
+			element: struct {
+				Foo netip.Prefix
+				Bar *big.Int
+				Baz net.IP
+			}{},
+			expected: expected{
+				node: &Node{Name: "traefik"},
+			},
+		},
+		{
+			desc: "TextMarshaler error",
+			element: struct {
+				Foo LogLevel
+			}{
+				Foo: LogLevel(42),
+			},
+			expected: expected{error: true},
+		},
 	}
 
 	for _, test := range testCases {
@@ -820,3 +868,51 @@ func TestEncodeToNode(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeToNode_redact(t *testing.T) {
+	type Auth struct {
+		User  string
+		Token string
+	}
+
+	element := struct {
+		Password string            `sensitive:"true"`
+		Empty    string            `sensitive:"true"`
+		Port     int               `sensitive:"true"`
+		Headers  map[string]string `sensitive:"true"`
+		Auth     *Auth             `sensitive:"true"`
+		Name     string
+	}{
+		Password: "admin",
+		Port:     8080,
+		Headers:  map[string]string{"X-Token": "abc"},
+		Auth:     &Auth{User: "root", Token: "xyz"},
+		Name:     "foo",
+	}
+
+	etnOpts := EncoderToNodeOpts{OmitEmpty: true, TagName: TagLabel, Redact: true}
+	node, err := EncodeToNode(element, DefaultRootName, etnOpts)
+	require.NoError(t, err)
+
+	expected := &Node{Name: "traefik", Children: []*Node{
+		{Name: "Password", FieldName: "Password", Value: RedactedValue},
+		{Name: "Port", FieldName: "Port", Value: RedactedValue},
+		{Name: "Headers", FieldName: "Headers", Children: []*Node{
+			{Name: "X-Token", FieldName: "X-Token", Value: RedactedValue},
+		}},
+		{Name: "Auth", FieldName: "Auth", Children: []*Node{
+			{Name: "User", FieldName: "User", Value: RedactedValue},
+			{Name: "Token", FieldName: "Token", Value: RedactedValue},
+		}},
+		{Name: "Name", FieldName: "Name", Value: "foo"},
+	}}
+	assert.Equal(t, expected, node)
+
+	// the sensitive values are kept when the redaction is disabled.
+	etnOpts.Redact = false
+	node, err = EncodeToNode(element, DefaultRootName, etnOpts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "admin", node.Get("Password").Value)
+	assert.Equal(t, "xyz", node.Get("Auth.Token").Value)
+}
@@ -0,0 +1,279 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DecodeError describes an error that occurred while decoding the configuration element at Path.
+type DecodeError struct {
+	// Path is the dotted path of the element (ex: traefik.entryPoints.web.address).
+	Path string
+	// Value is the raw value of the element.
+	Value string
+	// Type is the expected Go type, if known.
+	Type reflect.Type
+	// Origin is the origin of the value: label, flag, environment variable name, or file path.
+	Origin string
+	// Source is the source of the value, if known.
+	Source *Source
+	// Suggestion is the closest known key, when the key is unknown.
+	Suggestion string
+	Err        error
+
+	// parent is the parent node of an unknown node, used to build the suggested path.
+	parent *Node
+}
+
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+
+	b.WriteString(e.Path)
+
+	if e.Origin != "" {
+		b.WriteString(" (" + e.Origin + ")")
+	}
+
+	b.WriteString(": ")
+
+	if e.Type != nil {
+		b.WriteString(fmt.Sprintf("cannot decode %q as %s: ", e.Value, e.Type))
+	}
+
+	b.WriteString(e.Err.Error())
+
+	if e.Suggestion != "" {
+		b.WriteString(fmt.Sprintf(", did you mean %q?", e.Suggestion))
+	}
+
+	return b.String()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeErrors is a list of decode errors, returned when the errors are aggregated.
+type DecodeErrors []*DecodeError
+
+func (e DecodeErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, dErr := range e {
+		msgs = append(msgs, dErr.Error())
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the decode errors as a list of errors.
+func (e DecodeErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, dErr := range e {
+		errs = append(errs, dErr)
+	}
+
+	return errs
+}
+
+// Is reports whether one of the decode errors matches target.
+// It allows errors.Is to browse the decode errors before Go 1.20 (which doesn't support Unwrap() []error).
+func (e DecodeErrors) Is(target error) bool {
+	for _, dErr := range e {
+		if errors.Is(dErr, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first decode error that matches target, and if so, sets target to that error value.
+// It allows errors.As to browse the decode errors before Go 1.20 (which doesn't support Unwrap() []error).
+func (e DecodeErrors) As(target interface{}) bool {
+	for _, dErr := range e {
+		if errors.As(dErr, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// errorOrNil returns nil if there is no decode error.
+func (e DecodeErrors) errorOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// ErrFieldNotFound is the error returned when a key doesn't match any field.
+var ErrFieldNotFound = errors.New("field not found")
+
+// UnknownKeysError lists the keys which don't match any field.
+// It's returned with the UnknownKeysWarn policy, the element is filled with the other keys.
+type UnknownKeysError struct {
+	// Keys are the paths of the unknown keys, sorted.
+	Keys []string
+}
+
+func (e *UnknownKeysError) Error() string {
+	return "unknown keys: " + strings.Join(e.Keys, ", ")
+}
+
+// AsDecodeErrors returns the decode errors contained in err.
+func AsDecodeErrors(err error) []*DecodeError {
+	var dErrs DecodeErrors
+	if errors.As(err, &dErrs) {
+		return dErrs
+	}
+
+	var dErr *DecodeError
+	if errors.As(err, &dErr) {
+		return []*DecodeError{dErr}
+	}
+
+	return nil
+}
+
+// setOrigin sets the origin of the decode errors contained in err, if not already set.
+func setOrigin(err error, origin string) error {
+	for _, dErr := range AsDecodeErrors(err) {
+		if dErr.Origin == "" {
+			dErr.Origin = origin
+		}
+	}
+
+	return err
+}
+
+// wrapDecodeError wraps err into a DecodeError, unless err already contains one.
+func wrapDecodeError(err error, path string, node *Node, typ reflect.Type) error {
+	if err == nil {
+		return nil
+	}
+
+	var dErr *DecodeError
+	if errors.As(err, &dErr) {
+		return err
+	}
+
+	return &DecodeError{Path: path, Value: node.Value, Type: typ, Source: node.Source, Err: err}
+}
+
+// redactSensitiveErrors redacts the decode errors related to the sensitive values (see TagSensitive)
+// of the node and its children.
+func redactSensitiveErrors(err error, node *Node) error {
+	dErrs := AsDecodeErrors(err)
+	if len(dErrs) == 0 || node == nil {
+		return err
+	}
+
+	paths := getSensitivePaths(node, node.Name, nil)
+	if len(paths) == 0 {
+		return err
+	}
+
+	for _, dErr := range dErrs {
+		for _, path := range paths {
+			if isSubPath(dErr.Path, path) {
+				redactDecodeError(dErr)
+				break
+			}
+		}
+	}
+
+	return err
+}
+
+// getSensitivePaths returns the paths of the sensitive nodes (see Node.Sensitive and TagSensitive), the node being located at path.
+func getSensitivePaths(node *Node, path string, paths []string) []string {
+	if node.Sensitive || IsSensitive(node.Tag) {
+		return append(paths, path)
+	}
+
+	for _, child := range node.Children {
+		paths = getSensitivePaths(child, childPath(path, child.Name), paths)
+	}
+
+	return paths
+}
+
+// isSubPath reports whether path is the path of the element located at parent, or of one of its children.
+func isSubPath(path, parent string) bool {
+	if !strings.HasPrefix(path, parent) {
+		return false
+	}
+
+	rest := path[len(parent):]
+
+	return rest == "" || rest[0] == '.' || rest[0] == '['
+}
+
+// redactDecodeError replaces the value of the decode error by RedactedValue, in the error message too.
+func redactDecodeError(dErr *DecodeError) {
+	if dErr.Value == "" || dErr.Value == RedactedValue {
+		return
+	}
+
+	dErr.Err = &redactedError{err: dErr.Err, value: dErr.Value}
+	dErr.Value = RedactedValue
+}
+
+// redactedError is an error whose message doesn't contain the sensitive value.
+type redactedError struct {
+	err   error
+	value string
+}
+
+func (e *redactedError) Error() string {
+	msg := e.err.Error()
+
+	// the value can be quoted in the message (ex: strconv errors and %q), its special characters being escaped.
+	for _, quoted := range []string{strconv.Quote(e.value), strconv.QuoteToASCII(e.value)} {
+		msg = strings.ReplaceAll(msg, quoted, strconv.Quote(RedactedValue))
+		msg = strings.ReplaceAll(msg, quoted[1:len(quoted)-1], RedactedValue)
+	}
+
+	return strings.ReplaceAll(msg, e.value, RedactedValue)
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// hasDecodeError reports whether errs contains an error related to path.
+func hasDecodeError(errs []*DecodeError, path string) bool {
+	for _, dErr := range errs {
+		if dErr.Path == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortDecodeErrors sorts the decode errors by path.
+func sortDecodeErrors(errs DecodeErrors) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Path < errs[j].Path
+	})
+}
+
+// childPath returns the path of the child named name of the node at path.
+func childPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	if strings.HasPrefix(name, "[") {
+		return path + name
+	}
+
+	return path + "." + name
+}
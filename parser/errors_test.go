@@ -0,0 +1,378 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode_decodeError(t *testing.T) {
+	type Server struct {
+		URL    string
+		Weight int
+	}
+
+	type Tomato struct {
+		Name    string
+		Port    int
+		Servers []Server
+		Meta    map[string]uint8
+	}
+
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected DecodeError
+	}{
+		{
+			desc:   "invalid int",
+			labels: map[string]string{"traefik.port": "foo"},
+			expected: DecodeError{
+				Path:   "traefik.port",
+				Value:  "foo",
+				Type:   reflect.TypeOf(0),
+				Origin: "label",
+			},
+		},
+		{
+			desc:   "invalid int in slice of structs",
+			labels: map[string]string{"traefik.servers[0].weight": "heavy"},
+			expected: DecodeError{
+				Path:   "traefik.servers[0].weight",
+				Value:  "heavy",
+				Type:   reflect.TypeOf(0),
+				Origin: "label",
+			},
+		},
+		{
+			desc:   "invalid map value",
+			labels: map[string]string{"traefik.meta.foo": "256"},
+			expected: DecodeError{
+				Path:   "traefik.meta.foo",
+				Value:  "256",
+				Type:   reflect.TypeOf(uint8(0)),
+				Origin: "label",
+			},
+		},
+		{
+			desc:   "unknown field",
+			labels: map[string]string{"traefik.servers[0].foo": "bar"},
+			expected: DecodeError{
+				Path:   "traefik.servers[0].foo",
+				Value:  "bar",
+				Origin: "label",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := Decode(test.labels, &Tomato{}, DefaultRootName)
+			require.Error(t, err)
+
+			var dErr *DecodeError
+			require.True(t, errors.As(err, &dErr))
+
+			assert.Equal(t, test.expected.Path, dErr.Path)
+			assert.Equal(t, test.expected.Value, dErr.Value)
+			assert.Equal(t, test.expected.Type, dErr.Type)
+			assert.Equal(t, test.expected.Origin, dErr.Origin)
+			assert.Error(t, dErr.Err)
+		})
+	}
+}
+
+func TestDecodeError_Error(t *testing.T) {
+	err := &DecodeError{
+		Path:   "traefik.port",
+		Value:  "foo",
+		Type:   reflect.TypeOf(0),
+		Origin: "label",
+		Err:    errors.New("invalid syntax"),
+	}
+
+	assert.Equal(t, `traefik.port (label): cannot decode "foo" as int: invalid syntax`, err.Error())
+}
+
+func TestDecodeWithOpts_aggregateErrors(t *testing.T) {
+	type Server struct {
+		URL    string
+		Weight int
+	}
+
+	type Tomato struct {
+		Name    string
+		Port    int
+		Enabled bool
+		Servers []Server
+		Meta    map[string]uint8
+	}
+
+	labels := map[string]string{
+		"traefik.name":              "tomato",
+		"traefik.port":              "foo",
+		"traefik.enabled":           "yes",
+		"traefik.unknown":           "bar",
+		"traefik.servers[0].url":    "http://localhost",
+		"traefik.servers[0].weight": "heavy",
+		"traefik.servers[1].url":    "http://127.0.0.1",
+		"traefik.servers[1].foo":    "bar",
+		"traefik.meta.valid":        "42",
+		"traefik.meta.invalid":      "256",
+	}
+
+	element := &Tomato{}
+	err := DecodeWithOpts(labels, element, DefaultRootName, DecodeOpts{AggregateErrors: true})
+	require.Error(t, err)
+
+	var paths []string
+	for _, dErr := range AsDecodeErrors(err) {
+		assert.Equal(t, "label", dErr.Origin)
+		paths = append(paths, dErr.Path)
+	}
+
+	expectedPaths := []string{
+		"traefik.enabled",
+		"traefik.meta.invalid",
+		"traefik.port",
+		"traefik.servers[0].weight",
+		"traefik.servers[1].foo",
+		"traefik.unknown",
+	}
+	assert.Equal(t, expectedPaths, paths)
+
+	expected := &Tomato{
+		Name: "tomato",
+		Servers: []Server{
+			{URL: "http://localhost"},
+			{URL: "http://127.0.0.1"},
+		},
+		Meta: map[string]uint8{"valid": 42},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecodeWithOpts_noAggregateErrors(t *testing.T) {
+	type Tomato struct {
+		Port    int
+		Enabled bool
+	}
+
+	labels := map[string]string{
+		"traefik.port":    "foo",
+		"traefik.enabled": "yes",
+	}
+
+	err := DecodeWithOpts(labels, &Tomato{}, DefaultRootName, DecodeOpts{})
+	require.Error(t, err)
+
+	assert.Len(t, AsDecodeErrors(err), 1)
+}
+
+func TestDecodeErrors_Error(t *testing.T) {
+	err := DecodeErrors{
+		{Path: "traefik.port", Value: "foo", Type: reflect.TypeOf(0), Err: errors.New("invalid syntax")},
+		{Path: "traefik.unknown", Value: "bar", Err: errors.New("field not found, node: unknown")},
+	}
+
+	expected := `traefik.port: cannot decode "foo" as int: invalid syntax
+traefik.unknown: field not found, node: unknown`
+	assert.Equal(t, expected, err.Error())
+
+	var dErr *DecodeError
+	require.ErrorAs(t, err, &dErr)
+	assert.Equal(t, "traefik.port", dErr.Path)
+}
+
+func TestDecodeErrors_IsAs(t *testing.T) {
+	err := DecodeErrors{
+		{Path: "traefik.port", Value: "foo", Type: reflect.TypeOf(0), Err: errors.New("invalid syntax")},
+		{Path: "traefik.unknown", Value: "bar", Err: ErrFieldNotFound},
+		{Path: "traefik.name", Err: &ValidationError{Rule: "nonempty", Err: errors.New("the value must not be empty")}},
+	}
+
+	// the methods are called directly, as errors.Is and errors.As also follow Unwrap() []error since Go 1.20.
+	assert.True(t, err.Is(ErrFieldNotFound))
+	assert.False(t, err.Is(ErrMissingVariable))
+
+	var vErr *ValidationError
+	require.True(t, err.As(&vErr))
+	assert.Equal(t, "nonempty", vErr.Rule)
+
+	var dErr *DecodeError
+	require.True(t, err.As(&dErr))
+	assert.Equal(t, "traefik.port", dErr.Path)
+
+	assert.ErrorIs(t, err, ErrFieldNotFound)
+}
+
+func TestDecodeWithOpts_unknownKeys(t *testing.T) {
+	type Server struct {
+		URL string
+	}
+
+	type Tomato struct {
+		Name    string
+		Servers []Server
+	}
+
+	labels := map[string]string{
+		"traefik.name":              "tomato",
+		"traefik.unknown":           "bar",
+		"traefik.servers[0].url":    "http://localhost",
+		"traefik.servers[0].weight": "10",
+	}
+
+	testCases := []struct {
+		desc         string
+		policy       UnknownKeysPolicy
+		expectedKeys []string
+		expectedErr  bool
+	}{
+		{
+			desc:        "default",
+			expectedErr: true,
+		},
+		{
+			desc:        "strict",
+			policy:      UnknownKeysStrict,
+			expectedErr: true,
+		},
+		{
+			desc:         "warn",
+			policy:       UnknownKeysWarn,
+			expectedKeys: []string{"traefik.servers[0].weight", "traefik.unknown"},
+		},
+		{
+			desc:   "ignore",
+			policy: UnknownKeysIgnore,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			element := &Tomato{}
+			err := DecodeWithOpts(labels, element, DefaultRootName, DecodeOpts{UnknownKeys: test.policy})
+
+			if test.expectedErr {
+				require.ErrorIs(t, err, ErrFieldNotFound)
+				return
+			}
+
+			if test.expectedKeys != nil {
+				var unknownErr *UnknownKeysError
+				require.ErrorAs(t, err, &unknownErr)
+				assert.Equal(t, test.expectedKeys, unknownErr.Keys)
+			} else {
+				require.NoError(t, err)
+			}
+
+			expected := &Tomato{Name: "tomato", Servers: []Server{{URL: "http://localhost"}}}
+			assert.Equal(t, expected, element)
+		})
+	}
+}
+
+func TestDecodeWithOpts_unknownKeysAndErrors(t *testing.T) {
+	type Tomato struct {
+		Port int
+	}
+
+	labels := map[string]string{
+		"traefik.port":    "foo",
+		"traefik.unknown": "bar",
+	}
+
+	err := DecodeWithOpts(labels, &Tomato{}, DefaultRootName, DecodeOpts{UnknownKeys: UnknownKeysWarn})
+	require.Error(t, err)
+
+	var unknownErr *UnknownKeysError
+	assert.False(t, errors.As(err, &unknownErr))
+
+	dErrs := AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+	assert.Equal(t, "traefik.port", dErrs[0].Path)
+}
+
+func TestDecode_sensitiveDecodeError(t *testing.T) {
+	type Auth struct {
+		User string
+		Port int
+	}
+
+	type Tomato struct {
+		Name    string
+		Port    int              `sensitive:"true"`
+		Auth    *Auth            `sensitive:"true"`
+		Secrets map[string]uint8 `sensitive:"true"`
+		Key     string           `sensitive:"true" paerser-validate:"pattern=^[a-z]+$"`
+	}
+
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected string
+	}{
+		{
+			desc:     "invalid sensitive int",
+			labels:   map[string]string{"traefik.port": "p4ssw0rd"},
+			expected: `traefik.port (label): cannot decode "<redacted>" as int: strconv.ParseInt: parsing "<redacted>": invalid syntax`,
+		},
+		{
+			desc:     "invalid int in sensitive struct",
+			labels:   map[string]string{"traefik.auth.port": "p4ssw0rd"},
+			expected: `traefik.auth.port (label): cannot decode "<redacted>" as int: strconv.ParseInt: parsing "<redacted>": invalid syntax`,
+		},
+		{
+			desc:     "invalid value in sensitive map",
+			labels:   map[string]string{"traefik.secrets.foo": "p4ssw0rd"},
+			expected: `traefik.secrets.foo (label): cannot decode "<redacted>" as uint8: strconv.ParseUint: parsing "<redacted>": invalid syntax`,
+		},
+		{
+			desc:     "unknown field in sensitive struct",
+			labels:   map[string]string{"traefik.auth.usr": "p4ssw0rd"},
+			expected: `traefik.auth.usr (label): field not found, node: usr, did you mean "traefik.auth.user"?`,
+		},
+		{
+			desc:     "invalid sensitive int with special characters",
+			labels:   map[string]string{"traefik.port": `p4ss"w0rd\`},
+			expected: `traefik.port (label): cannot decode "<redacted>" as int: strconv.ParseInt: parsing "<redacted>": invalid syntax`,
+		},
+		{
+			desc:     "invalid sensitive int with non-ASCII characters",
+			labels:   map[string]string{"traefik.port": "p4ss\tw0rd\u00e9"},
+			expected: `traefik.port (label): cannot decode "<redacted>" as int: strconv.ParseInt: parsing "<redacted>": invalid syntax`,
+		},
+		{
+			desc:     "invalid sensitive value with special characters",
+			labels:   map[string]string{"traefik.key": `p4ss"w0rd\`},
+			expected: `traefik.key (label): validation failed (pattern=^[a-z]+$): "<redacted>" must match the pattern ^[a-z]+$`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := Decode(test.labels, &Tomato{}, DefaultRootName)
+			require.Error(t, err)
+
+			var dErr *DecodeError
+			require.True(t, errors.As(err, &dErr))
+
+			assert.Equal(t, RedactedValue, dErr.Value)
+			assert.Equal(t, test.expected, err.Error())
+			assert.NotContains(t, err.Error(), "p4ss")
+		})
+	}
+}
@@ -4,11 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
-	"strconv"
 	"strings"
-	"time"
-
-	"github.com/traefik/paerser/types"
 )
 
 const defaultPtrValue = "false"
@@ -26,6 +22,7 @@ type Flat struct {
 	Name        string
 	Description string
 	Default     string
+	Constraints string
 }
 
 // EncodeToFlat encodes a node to a Flat representation.
@@ -51,10 +48,10 @@ func EncodeToFlat(element interface{}, node *Node, opts FlatOpts) ([]Flat, error
 	if encoder.SkipRoot {
 		for _, child := range node.Children {
 			field := encoder.getField(elem.Elem(), child)
-			entries = append(entries, encoder.createFlat(field, child.Name, child)...)
+			entries = append(entries, encoder.createFlat(field, child.Name, child, false)...)
 		}
 	} else {
-		entries = encoder.createFlat(elem, strings.ToLower(node.Name), node)
+		entries = encoder.createFlat(elem, strings.ToLower(node.Name), node, false)
 	}
 
 	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
@@ -66,7 +63,11 @@ type encoderToFlat struct {
 	FlatOpts
 }
 
-func (e encoderToFlat) createFlat(field reflect.Value, name string, node *Node) []Flat {
+// createFlat creates the Flat entries of the node and its children,
+// the default values of the sensitive nodes (see Node.Sensitive and TagSensitive) and of their children are redacted.
+func (e encoderToFlat) createFlat(field reflect.Value, name string, node *Node, sensitive bool) []Flat {
+	sensitive = sensitive || node.Sensitive || IsSensitive(node.Tag)
+
 	var entries []Flat
 	if node.Kind != reflect.Map && node.Description != "-" {
 		if !(node.Kind == reflect.Pointer && len(node.Children) > 0) ||
@@ -75,7 +76,8 @@ func (e encoderToFlat) createFlat(field reflect.Value, name string, node *Node)
 				entries = append(entries, Flat{
 					Name:        e.getName(name),
 					Description: node.Description,
-					Default:     e.getNodeValue(e.getField(field, node), node),
+					Default:     e.getNodeValue(e.getField(field, node), node, sensitive),
+					Constraints: GetConstraints(node.Tag),
 				})
 			}
 		}
@@ -86,10 +88,10 @@ func (e encoderToFlat) createFlat(field reflect.Value, name string, node *Node)
 			fChild := e.getField(field, child)
 
 			var v string
-			if child.Kind == reflect.Struct {
+			if child.Kind == reflect.Struct && !(fChild.IsValid() && IsCustomType(fChild.Type())) {
 				v = defaultPtrValue
 			} else {
-				v = e.getNodeValue(fChild, child)
+				v = e.getNodeValue(fChild, child, sensitive)
 			}
 
 			if node.Description != "-" {
@@ -104,13 +106,13 @@ func (e encoderToFlat) createFlat(field reflect.Value, name string, node *Node)
 				for _, ch := range child.Children {
 					f := e.getField(fChild, ch)
 					n := e.getName(name, child.Name, ch.Name)
-					entries = append(entries, e.createFlat(f, n, ch)...)
+					entries = append(entries, e.createFlat(f, n, ch, sensitive)...)
 				}
 			}
 		} else {
 			f := e.getField(field, child)
 			n := e.getName(name, child.Name)
-			entries = append(entries, e.createFlat(f, n, child)...)
+			entries = append(entries, e.createFlat(f, n, child, sensitive)...)
 		}
 	}
 
@@ -133,19 +135,31 @@ func (e encoderToFlat) getField(field reflect.Value, node *Node) reflect.Value {
 	}
 }
 
-func (e encoderToFlat) getNodeValue(field reflect.Value, node *Node) string {
+func (e encoderToFlat) getNodeValue(field reflect.Value, node *Node, sensitive bool) string {
 	if node.Kind == reflect.Pointer && len(node.Children) > 0 {
 		return defaultPtrValue
 	}
 
-	if field.Kind() == reflect.Int64 {
-		i, _ := strconv.ParseInt(node.Value, 10, 64)
+	value := e.getValue(field, node)
+	if sensitive && value != "" {
+		return RedactedValue
+	}
+
+	return value
+}
+
+func (e encoderToFlat) getValue(field reflect.Value, node *Node) string {
+	if value, ok := node.Tag.Lookup(TagDefault); ok && (!field.IsValid() || field.IsZero()) {
+		return value
+	}
+
+	if field.Kind() == reflect.Pointer && !field.IsNil() {
+		field = field.Elem()
+	}
 
-		switch field.Type() {
-		case reflect.TypeOf(types.Duration(time.Second)):
-			return strconv.Itoa(int(i) / int(time.Second))
-		case reflect.TypeOf(time.Second):
-			return time.Duration(i).String()
+	if field.IsValid() && isCustomMarshaler(field.Type()) {
+		if value, err := getCustomValue(field); err == nil {
+			return value
 		}
 	}
 
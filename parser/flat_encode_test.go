@@ -1209,6 +1209,63 @@ func TestEncodeToFlat(t *testing.T) {
 			},
 		},
 		// Skipped: because realistically not needed in Traefik for now.
+		{
+			desc: "sensitive fields",
+			element: &struct {
+				Password string `description:"password description" sensitive:"true" default:"admin"`
+				Auth     *struct {
+					User string `description:"user description"`
+				} `description:"auth description" sensitive:"true"`
+			}{
+				Auth: &struct {
+					User string `description:"user description"`
+				}{
+					User: "root",
+				},
+			},
+			node: &Node{
+				Name: "traefik",
+				Kind: reflect.Pointer,
+				Children: []*Node{
+					{
+						Name:        "Password",
+						FieldName:   "Password",
+						Description: "password description",
+						Kind:        reflect.String,
+						Tag:         `description:"password description" sensitive:"true" default:"admin"`,
+					},
+					{
+						Name:        "Auth",
+						FieldName:   "Auth",
+						Description: "auth description",
+						Kind:        reflect.Pointer,
+						Tag:         `description:"auth description" sensitive:"true"`,
+						Children: []*Node{
+							{
+								Name:        "User",
+								FieldName:   "User",
+								Description: "user description",
+								Value:       "root",
+								Kind:        reflect.String,
+								Tag:         `description:"user description"`,
+							},
+						},
+					},
+				},
+			},
+			expected: []Flat{
+				{
+					Name:        "auth.user",
+					Description: "user description",
+					Default:     RedactedValue,
+				},
+				{
+					Name:        "password",
+					Description: "password description",
+					Default:     RedactedValue,
+				},
+			},
+		},
 		// {
 		// 	desc: "map of map field level 2",
 		// 	element: &struct {
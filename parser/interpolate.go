@@ -0,0 +1,266 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrMissingVariable is returned when an interpolated variable is not set, and has no default value.
+var ErrMissingVariable = errors.New("missing variable")
+
+var errNestedReference = errors.New("nested references are not supported")
+
+// Interpolate expands the references to the environment variables in value:
+// ${VAR} is replaced by the value of VAR, and ${VAR:-default} by the default value if VAR is not set or empty.
+// "$$" is an escaped "$", and a "$" not followed by "{" is kept as is.
+// The nested references (ex: ${VAR:-${DEFAULT}}) are not supported.
+// The environment variables are retrieved with lookupEnv (os.LookupEnv if nil).
+func Interpolate(value string, lookupEnv func(string) (string, bool)) (string, error) {
+	value, _, err := expander{env: true, lookupEnv: lookupEnv}.expand(value)
+	return value, err
+}
+
+// expander expands the references (${...}) in the values.
+type expander struct {
+	// env enables the references to the environment variables (${VAR} or ${VAR:-default}).
+	env       bool
+	lookupEnv func(string) (string, bool)
+	// resolve enables the references resolved by the registered resolvers (${scheme:reference}).
+	resolve bool
+}
+
+// expand expands the references in value, and reports whether a resolved reference (a secret) is included.
+// The disabled references are kept as is.
+func (x expander) expand(value string) (string, bool, error) {
+	if !strings.Contains(value, "$") {
+		return value, false, nil
+	}
+
+	var b strings.Builder
+	var secret bool
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+
+		switch value[i+1] {
+		case '$':
+			// the escaped "$" are kept as is when the interpolation of the environment variables is disabled.
+			if !x.env {
+				b.WriteByte('$')
+			}
+			b.WriteByte('$')
+			i++
+
+		case '{':
+			end := strings.IndexByte(value[i+2:], '}')
+			if end < 0 {
+				return "", false, fmt.Errorf("unterminated variable reference: %s", value[i:])
+			}
+
+			reference := value[i+2 : i+2+end]
+
+			v, resolved, err := x.expandReference(reference)
+			if errors.Is(err, errNestedReference) {
+				return "", false, fmt.Errorf("%w: %s", err, value[i:])
+			}
+			if err != nil {
+				return "", false, err
+			}
+
+			b.WriteString(v)
+			secret = secret || resolved
+			i += end + 2
+
+		default:
+			b.WriteByte('$')
+		}
+	}
+
+	return b.String(), secret, nil
+}
+
+func (x expander) expandReference(reference string) (string, bool, error) {
+	if scheme, ref, ok := strings.Cut(reference, ":"); ok && !strings.HasPrefix(ref, "-") {
+		if !x.resolve {
+			return "${" + reference + "}", false, nil
+		}
+
+		if strings.Contains(ref, "${") {
+			return "", false, errNestedReference
+		}
+
+		value, err := resolve(scheme, ref)
+		if err != nil {
+			return "", false, err
+		}
+
+		return value, true, nil
+	}
+
+	if !x.env {
+		return "${" + reference + "}", false, nil
+	}
+
+	if strings.Contains(reference, "${") {
+		return "", false, errNestedReference
+	}
+
+	value, err := x.lookupVariable(reference)
+
+	return value, false, err
+}
+
+func (x expander) lookupVariable(reference string) (string, error) {
+	name, defaultValue, hasDefault := strings.Cut(reference, ":-")
+
+	if !isVariableName(name) {
+		return "", fmt.Errorf("invalid variable reference: ${%s}", reference)
+	}
+
+	lookupEnv := x.lookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	value, ok := lookupEnv(name)
+	if ok && value != "" {
+		return value, nil
+	}
+
+	if hasDefault {
+		return defaultValue, nil
+	}
+
+	if ok {
+		return value, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrMissingVariable, name)
+}
+
+func isVariableName(name string) bool {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return false
+	}
+
+	for _, c := range name {
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// expandNode expands the references in the values of the node and its children (see Interpolate and RegisterResolver),
+// the node being located at path.
+// The nodes including a resolved reference are marked as sensitive.
+func (f filler) expandNode(x expander, node *Node, path string) error {
+	var errs DecodeErrors
+
+	for _, child := range node.Children {
+		err := f.expandNode(x, child, childPath(path, child.Name))
+		if err != nil {
+			if !f.AggregateErrors {
+				return err
+			}
+
+			errs = append(errs, AsDecodeErrors(err)...)
+		}
+	}
+
+	if node.Value != "" {
+		value, secret, err := x.expand(node.Value)
+		if err != nil {
+			dErr := &DecodeError{Path: path, Value: node.Value, Source: node.Source, Err: err}
+			if !f.AggregateErrors {
+				return dErr
+			}
+
+			errs = append(errs, dErr)
+		} else {
+			node.Value = value
+			node.Sensitive = node.Sensitive || secret
+		}
+	}
+
+	if node.RawValue != nil {
+		value, secret, err := f.expandRawValue(x, node.RawValue, path, node.Source)
+		if err != nil {
+			if !f.AggregateErrors {
+				return err
+			}
+
+			errs = append(errs, AsDecodeErrors(err)...)
+		} else {
+			node.RawValue = value
+			node.Sensitive = node.Sensitive || secret
+		}
+	}
+
+	return errs.errorOrNil()
+}
+
+// expandRawValue expands the references in the strings of a raw value, the maps and the slices are copied.
+// It reports whether a resolved reference (a secret) is included.
+func (f filler) expandRawValue(x expander, raw interface{}, path string, source *Source) (interface{}, bool, error) {
+	var errs DecodeErrors
+	var secret bool
+
+	switch v := raw.(type) {
+	case string:
+		value, resolved, err := x.expand(v)
+		if err != nil {
+			return nil, false, &DecodeError{Path: path, Value: v, Source: source, Err: err}
+		}
+
+		return value, resolved, nil
+
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elt := range v {
+			value, resolved, err := f.expandRawValue(x, elt, childPath(path, key), source)
+			if err != nil {
+				if !f.AggregateErrors {
+					return nil, false, err
+				}
+
+				errs = append(errs, AsDecodeErrors(err)...)
+				continue
+			}
+
+			m[key] = value
+			secret = secret || resolved
+		}
+		raw = m
+
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, elt := range v {
+			value, resolved, err := f.expandRawValue(x, elt, fmt.Sprintf("%s[%d]", path, i), source)
+			if err != nil {
+				if !f.AggregateErrors {
+					return nil, false, err
+				}
+
+				errs = append(errs, AsDecodeErrors(err)...)
+				continue
+			}
+
+			s[i] = value
+			secret = secret || resolved
+		}
+		raw = s
+	}
+
+	if err := errs.errorOrNil(); err != nil {
+		return nil, false, err
+	}
+
+	return raw, secret, nil
+}
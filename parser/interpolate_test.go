@@ -0,0 +1,321 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	env := map[string]string{
+		"HOST":  "localhost",
+		"PORT":  "8080",
+		"EMPTY": "",
+	}
+
+	testCases := []struct {
+		desc     string
+		value    string
+		expected string
+	}{
+		{
+			desc:     "no reference",
+			value:    "http://localhost",
+			expected: "http://localhost",
+		},
+		{
+			desc:     "references",
+			value:    "http://${HOST}:${PORT}/",
+			expected: "http://localhost:8080/",
+		},
+		{
+			desc:     "default value of a missing variable",
+			value:    "${MISSING:-http://default}",
+			expected: "http://default",
+		},
+		{
+			desc:     "default value of an empty variable",
+			value:    "${EMPTY:-foo}",
+			expected: "foo",
+		},
+		{
+			desc:     "default value of a set variable",
+			value:    "${HOST:-foo}",
+			expected: "localhost",
+		},
+		{
+			desc:     "empty default value",
+			value:    "a${MISSING:-}b",
+			expected: "ab",
+		},
+		{
+			desc:     "empty variable",
+			value:    "a${EMPTY}b",
+			expected: "ab",
+		},
+		{
+			desc:     "escaped dollar",
+			value:    "$${HOST} costs 5$$",
+			expected: "${HOST} costs 5$",
+		},
+		{
+			desc:     "dollar without brace",
+			value:    "^/foo$|$HOST",
+			expected: "^/foo$|$HOST",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := Interpolate(test.value, lookupEnv(env))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestInterpolate_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    string
+		expected string
+	}{
+		{
+			desc:     "missing variable",
+			value:    "http://${MISSING}",
+			expected: "missing variable: MISSING",
+		},
+		{
+			desc:     "unterminated reference",
+			value:    "http://${HOST",
+			expected: "unterminated variable reference: ${HOST",
+		},
+		{
+			desc:     "invalid name",
+			value:    "${1HOST}",
+			expected: "invalid variable reference: ${1HOST}",
+		},
+		{
+			desc:     "empty reference",
+			value:    "${}",
+			expected: "invalid variable reference: ${}",
+		},
+		{
+			desc:     "nested reference",
+			value:    "${FOO:-${HOST}}",
+			expected: "nested references are not supported: ${FOO:-${HOST}}",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Interpolate(test.value, lookupEnv(map[string]string{"HOST": "localhost"}))
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestDecodeWithOpts_interpolate(t *testing.T) {
+	labels := map[string]string{
+		"traefik.foo":             "${FOO}",
+		"traefik.bar":             "${BAR:-bar}",
+		"traefik.names":           "${FOO},b",
+		"traefik.raw.baz.values":  "$${ESCAPED}",
+		"traefik.servers[0].port": "${PORT}",
+	}
+
+	element := &struct {
+		Foo     string
+		Bar     string
+		Names   []string
+		Raw     map[string]interface{}
+		Servers []struct {
+			Port int
+		}
+	}{}
+
+	opts := DecodeOpts{
+		Interpolate: true,
+		LookupEnv:   lookupEnv(map[string]string{"FOO": "foo", "PORT": "80"}),
+	}
+
+	err := DecodeWithOpts(labels, element, DefaultRootName, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", element.Foo)
+	assert.Equal(t, "bar", element.Bar)
+	assert.Equal(t, []string{"foo", "b"}, element.Names)
+	assert.Equal(t, map[string]interface{}{"baz": map[string]interface{}{"values": "${ESCAPED}"}}, element.Raw)
+	require.Len(t, element.Servers, 1)
+	assert.Equal(t, 80, element.Servers[0].Port)
+}
+
+func TestDecodeWithOpts_interpolate_disabled(t *testing.T) {
+	labels := map[string]string{
+		"traefik.foo": "${FOO}",
+	}
+
+	element := &struct {
+		Foo string
+	}{}
+
+	err := DecodeWithOpts(labels, element, DefaultRootName, DecodeOpts{LookupEnv: lookupEnv(map[string]string{"FOO": "foo"})})
+	require.NoError(t, err)
+
+	assert.Equal(t, "${FOO}", element.Foo)
+}
+
+func TestDecodeWithOpts_interpolate_missingVariables(t *testing.T) {
+	labels := map[string]string{
+		"traefik.foo":          "${FOO}",
+		"traefik.bar":          "${BAR}",
+		"traefik.raw.baz.fuu":  "${FUU}",
+		"traefik.raw.baz.fii":  "fii",
+		"traefik.servers.port": "${PORT:-80}",
+	}
+
+	element := &struct {
+		Foo     string
+		Bar     string
+		Raw     map[string]interface{}
+		Servers struct {
+			Port int
+		}
+	}{}
+
+	opts := DecodeOpts{
+		AggregateErrors: true,
+		Interpolate:     true,
+		LookupEnv:       lookupEnv(map[string]string{"BAR": "bar"}),
+	}
+
+	err := DecodeWithOpts(labels, element, DefaultRootName, opts)
+	require.Error(t, err)
+
+	dErrs := AsDecodeErrors(err)
+	require.Len(t, dErrs, 2)
+
+	assert.Equal(t, "traefik.foo", dErrs[0].Path)
+	assert.Equal(t, "${FOO}", dErrs[0].Value)
+	assert.ErrorIs(t, dErrs[0], ErrMissingVariable)
+	assert.Equal(t, "traefik.foo (label): missing variable: FOO", dErrs[0].Error())
+
+	assert.Equal(t, "traefik.raw.baz.fuu", dErrs[1].Path)
+	assert.ErrorIs(t, dErrs[1], ErrMissingVariable)
+}
+
+func TestDecodeWithOpts_interpolate_allowEmpty(t *testing.T) {
+	type Element struct {
+		P *struct {
+			Foo string
+		} `label:"allowEmpty"`
+	}
+
+	testCases := []struct {
+		desc     string
+		enable   string
+		expected bool
+	}{
+		{
+			desc:     "enabled",
+			enable:   "true",
+			expected: true,
+		},
+		{
+			desc:   "disabled",
+			enable: "false",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			labels := map[string]string{"traefik.p": "${ENABLE}"}
+
+			opts := DecodeOpts{
+				Interpolate: true,
+				LookupEnv:   lookupEnv(map[string]string{"ENABLE": test.enable}),
+			}
+
+			element := &Element{}
+			err := DecodeWithOpts(labels, element, DefaultRootName, opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, element.P != nil)
+		})
+	}
+}
+
+func TestFill_expandOnlyResolved(t *testing.T) {
+	element := &struct {
+		Password string
+		Foo      string
+	}{}
+
+	node := &Node{
+		Name: "traefik",
+		Kind: reflect.Pointer,
+		Children: []*Node{
+			{Name: "Password", FieldName: "Password", Kind: reflect.String, Value: "pa$$word"},
+			{Name: "Foo", FieldName: "Foo", Kind: reflect.String, Value: "${FOO}"},
+		},
+	}
+
+	err := Fill(element, node, FillerOpts{Resolve: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "pa$$word", element.Password)
+	assert.Equal(t, "${FOO}", element.Foo)
+}
+
+func TestFill_expandCopy(t *testing.T) {
+	element := &struct {
+		Foo string
+		Raw map[string]interface{}
+	}{}
+
+	node := &Node{
+		Name: "traefik",
+		Kind: reflect.Pointer,
+		Children: []*Node{
+			{Name: "Foo", FieldName: "Foo", Kind: reflect.String, Value: "${FOO}"},
+			{Name: "Raw", FieldName: "Raw", Kind: reflect.Map, RawValue: map[string]interface{}{"bar": "${FOO}"}},
+		},
+	}
+
+	opts := FillerOpts{Interpolate: true, LookupEnv: lookupEnv(map[string]string{"FOO": "foo"})}
+
+	err := Fill(element, node, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", element.Foo)
+	assert.Equal(t, map[string]interface{}{"bar": "foo"}, element.Raw)
+
+	// the node is not modified, it can be filled again.
+	assert.Equal(t, "${FOO}", node.Children[0].Value)
+	assert.Equal(t, map[string]interface{}{"bar": "${FOO}"}, node.Children[1].RawValue)
+
+	element.Foo = ""
+	err = Fill(element, node, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", element.Foo)
+}
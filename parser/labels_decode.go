@@ -8,6 +8,7 @@ import (
 
 // DecodeToNode converts the labels to a tree of nodes.
 // If any filters are present, labels which do not match the filters are skipped.
+// The source of each node is the related label (see SetSources).
 func DecodeToNode(labels map[string]string, rootName string, filters ...string) (*Node, error) {
 	sortedKeys := sortKeys(labels, filters)
 
@@ -43,6 +44,20 @@ func DecodeToNode(labels map[string]string, rootName string, filters ...string)
 		decodeToNode(node, parts, labels[key])
 	}
 
+	if node != nil {
+		keys := make(map[string]string, len(sortedKeys))
+		for _, key := range sortedKeys {
+			keys[strings.ToLower(key)] = key
+		}
+
+		SetSources(node, func(path string) *Source {
+			if key, ok := keys[strings.ToLower(path)]; ok {
+				return &Source{Kind: SourceLabel, Name: key}
+			}
+			return nil
+		})
+	}
+
 	return node, nil
 }
 
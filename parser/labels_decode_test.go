@@ -249,6 +249,9 @@ func TestDecodeToNode(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 
+				// the sources are checked by TestDecodeToNode_sources.
+				clearSources(out)
+
 				if !assert.Equal(t, test.expected.node, out) {
 					bytes, err := json.MarshalIndent(out, "", "  ")
 					require.NoError(t, err)
@@ -258,3 +261,30 @@ func TestDecodeToNode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeToNode_sources(t *testing.T) {
+	labels := map[string]string{
+		"traefik.Foo.bar":      "a",
+		"traefik.fii[0].field": "b",
+		"other.foo":            "c",
+	}
+
+	node, err := DecodeToNode(labels, DefaultRootName, "traefik.")
+	require.NoError(t, err)
+
+	assert.Equal(t, &Source{Kind: SourceLabel, Name: "traefik.Foo.bar"}, node.Get("foo.bar").Source)
+	assert.Equal(t, &Source{Kind: SourceLabel, Name: "traefik.Foo.bar"}, node.Get("foo").Source)
+	assert.Equal(t, &Source{Kind: SourceLabel, Name: "traefik.fii[0].field"}, node.Get("fii[0].field").Source)
+	assert.NotNil(t, node.Source)
+}
+
+func clearSources(node *Node) {
+	if node == nil {
+		return
+	}
+
+	node.Source = nil
+	for _, child := range node.Children {
+		clearSources(child)
+	}
+}
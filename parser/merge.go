@@ -0,0 +1,328 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// rawSliceSeparator is the separator of the typed slices encoded by the file decoder (ex: "║24║foo║bar").
+const rawSliceSeparator = "║"
+
+// MergeStrategy is a strategy to merge the values of a field (see TagMerge).
+type MergeStrategy string
+
+// Merge strategies.
+const (
+	// MergeReplace replaces the base value by the override value.
+	MergeReplace MergeStrategy = "replace"
+	// MergeAppend appends the elements of the override slice to the elements of the base slice.
+	MergeAppend MergeStrategy = "append"
+	// MergeIndex merges the elements of the slices by index.
+	MergeIndex MergeStrategy = "index"
+	// MergeDeep merges the entries of the maps recursively.
+	MergeDeep MergeStrategy = "deep"
+)
+
+// MergeOpts are the options of MergeNodes.
+type MergeOpts struct {
+	// SliceStrategy is the strategy of the slices without merge tag: MergeReplace (default), MergeAppend, or MergeIndex.
+	SliceStrategy MergeStrategy
+	// MapStrategy is the strategy of the maps without merge tag: MergeDeep (default), or MergeReplace.
+	MapStrategy MergeStrategy
+}
+
+// MergeNodes merges the override tree into the base tree, and returns the resulting tree.
+// The values of override take precedence, the structs are merged recursively,
+// and the slices and the maps are merged according to their strategy (see TagMerge and MergeOpts).
+// A disabled node of override (ex: an "allowEmpty" field set to "false") removes the related subtree of base.
+// The strategies are read from the tags of the nodes, so the trees are expected to be augmented with metadata (see AddMetadata),
+// otherwise the slices are only recognized by the names of their elements (ex: [0]).
+// The given trees are not modified.
+func MergeNodes(base, override *Node, opts MergeOpts) (*Node, error) {
+	if opts.SliceStrategy == "" {
+		opts.SliceStrategy = MergeReplace
+	}
+
+	if opts.MapStrategy == "" {
+		opts.MapStrategy = MergeDeep
+	}
+
+	if base == nil {
+		return cloneNode(override), nil
+	}
+
+	if override == nil {
+		return cloneNode(base), nil
+	}
+
+	result := cloneNode(base)
+
+	if err := (merger{MergeOpts: opts}).merge(result, override, result.Name); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type merger struct {
+	MergeOpts
+}
+
+// merge merges the override node into the node (a copy of the base node) located at path.
+func (m merger) merge(node, override *Node, path string) error {
+	mergeMetadata(node, override)
+
+	if node.Kind == reflect.Map {
+		strategy, err := getMergeStrategy(node, m.MapStrategy, path, MergeDeep, MergeReplace)
+		if err != nil {
+			return err
+		}
+
+		if strategy == MergeReplace {
+			*node = *cloneNode(override)
+			return nil
+		}
+	}
+
+	if override.RawValue != nil {
+		node.RawValue = mergeRawValues(node.RawValue, override.RawValue)
+		node.Source = override.Source
+		node.Sensitive = node.Sensitive || override.Sensitive
+		return nil
+	}
+
+	if node.Kind == reflect.Slice || isSliceNode(node) || isSliceNode(override) {
+		strategy, err := getMergeStrategy(node, m.SliceStrategy, path, MergeReplace, MergeAppend, MergeIndex)
+		if err != nil {
+			return err
+		}
+
+		return m.mergeSlice(node, override, path, strategy)
+	}
+
+	if len(override.Children) == 0 || override.Value != "" {
+		node.Value = override.Value
+		node.Source = override.Source
+		node.Sensitive = override.Sensitive
+	}
+
+	return m.mergeChildren(node, override, path)
+}
+
+func (m merger) mergeSlice(node, override *Node, path string, strategy MergeStrategy) error {
+	switch {
+	case strategy == MergeReplace || (len(node.Children) > 0) != (len(override.Children) > 0):
+		*node = *cloneNode(override)
+
+	case len(override.Children) == 0:
+		// slice of values
+		node.Value = mergeSliceValues(node.Value, override.Value, strategy)
+		node.Source = override.Source
+		node.Sensitive = node.Sensitive || override.Sensitive
+
+	case strategy == MergeAppend:
+		offset := len(node.Children)
+		for i, child := range override.Children {
+			elt := cloneNode(child)
+			elt.Name = fmt.Sprintf("[%d]", offset+i)
+			node.Children = append(node.Children, elt)
+		}
+
+	default:
+		return m.mergeChildren(node, override, path)
+	}
+
+	return nil
+}
+
+func (m merger) mergeChildren(node, override *Node, path string) error {
+	for _, child := range override.Children {
+		index := findChildIndex(node, child.Name)
+
+		if child.Disabled {
+			if index >= 0 {
+				node.Children = append(node.Children[:index], node.Children[index+1:]...)
+			}
+			continue
+		}
+
+		if index < 0 {
+			node.Children = append(node.Children, cloneNode(child))
+			continue
+		}
+
+		if err := m.merge(node.Children[index], child, childPath(path, child.Name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findChildIndex returns the index of the child with the given name, or -1.
+// The names of the map entries are case-sensitive, the names of the fields are not.
+func findChildIndex(node *Node, name string) int {
+	for i, child := range node.Children {
+		if child.Name == name || node.Kind != reflect.Map && strings.EqualFold(child.Name, name) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// mergeMetadata completes the metadata of the node with the metadata of the override node.
+func mergeMetadata(node, override *Node) {
+	if override.FieldName != "" {
+		node.FieldName = override.FieldName
+	}
+
+	if override.Description != "" {
+		node.Description = override.Description
+	}
+
+	if override.Kind != reflect.Invalid {
+		node.Kind = override.Kind
+	}
+
+	if override.Tag != "" {
+		node.Tag = override.Tag
+	}
+}
+
+func getMergeStrategy(node *Node, defaultStrategy MergeStrategy, path string, allowed ...MergeStrategy) (MergeStrategy, error) {
+	strategy := MergeStrategy(node.Tag.Get(TagMerge))
+	if strategy == "" {
+		strategy = defaultStrategy
+	}
+
+	for _, s := range allowed {
+		if s == strategy {
+			return strategy, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: invalid merge strategy %q (kind %s)", path, strategy, node.Kind)
+}
+
+// isSliceNode returns true if the children of the node are the elements of a slice.
+func isSliceNode(node *Node) bool {
+	return len(node.Children) > 0 && isArrayKey(node.Children[0].Name)
+}
+
+// mergeSliceValues merges two slices of values, represented as a single value (ex: "foo,bar" or "║24║foo║bar").
+func mergeSliceValues(base, override string, strategy MergeStrategy) string {
+	baseType, values := splitSliceValue(base)
+	overrideType, overrideValues := splitSliceValue(override)
+
+	if strategy == MergeAppend {
+		values = append(values, overrideValues...)
+	} else {
+		for i, value := range overrideValues {
+			if i < len(values) {
+				values[i] = value
+			} else {
+				values = append(values, value)
+			}
+		}
+	}
+
+	typ := overrideType
+	if typ == "" {
+		typ = baseType
+	}
+
+	if typ != "" {
+		return rawSliceSeparator + typ + rawSliceSeparator + strings.Join(values, rawSliceSeparator)
+	}
+
+	return strings.Join(values, defaultRawSliceSeparator)
+}
+
+// splitSliceValue returns the type (only for the typed slices) and the elements of a slice represented as a single value.
+func splitSliceValue(value string) (string, []string) {
+	if value == "" {
+		return "", nil
+	}
+
+	if strings.HasPrefix(value, rawSliceSeparator) {
+		parts := strings.Split(value, rawSliceSeparator)
+		if len(parts) >= 2 {
+			return parts[1], parts[2:]
+		}
+	}
+
+	return "", strings.Split(value, defaultRawSliceSeparator)
+}
+
+// mergeRawValues merges the raw values (map[string]interface{}) recursively, the override values take precedence.
+// The result is a copy, it doesn't share any map or slice with the given values.
+func mergeRawValues(base, override interface{}) interface{} {
+	baseMap, ok := base.(map[string]interface{})
+	if !ok {
+		return cloneRawValue(override)
+	}
+
+	overrideMap, ok := override.(map[string]interface{})
+	if !ok {
+		return cloneRawValue(override)
+	}
+
+	result := make(map[string]interface{}, len(baseMap))
+	for k, v := range baseMap {
+		result[k] = cloneRawValue(v)
+	}
+
+	for k, v := range overrideMap {
+		result[k] = mergeRawValues(baseMap[k], v)
+	}
+
+	return result
+}
+
+func cloneNode(node *Node) *Node {
+	if node == nil {
+		return nil
+	}
+
+	clone := *node
+	clone.RawValue = cloneRawValue(node.RawValue)
+
+	clone.Children = nil
+	for _, child := range node.Children {
+		clone.Children = append(clone.Children, cloneNode(child))
+	}
+
+	return &clone
+}
+
+// cloneRawValue returns a deep copy of the maps and the slices of a raw value.
+func cloneRawValue(raw interface{}) interface{} {
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return raw
+		}
+
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = cloneRawValue(value)
+		}
+		return m
+
+	case []interface{}:
+		if v == nil {
+			return raw
+		}
+
+		s := make([]interface{}, len(v))
+		for i, value := range v {
+			s[i] = cloneRawValue(value)
+		}
+		return s
+
+	default:
+		return raw
+	}
+}
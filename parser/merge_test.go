@@ -0,0 +1,305 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MergedConfig struct {
+	Foo      string
+	Bar      string
+	Names    []string
+	Appended []string `merge:"append"`
+	Indexed  []string `merge:"index"`
+	Servers  []MergedServer
+	Backends []MergedServer `merge:"append"`
+	Routes   map[string]*MergedServer
+	Headers  map[string]string `merge:"replace"`
+	Health   *MergedHealth     `label:"allowEmpty"`
+}
+
+type MergedServer struct {
+	URL    string
+	Weight int
+}
+
+type MergedHealth struct {
+	Path string
+}
+
+func TestMergeNodes(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		base     map[string]string
+		override map[string]string
+		opts     MergeOpts
+		expected *MergedConfig
+	}{
+		{
+			desc:     "override values",
+			base:     map[string]string{"traefik.foo": "a", "traefik.bar": "b"},
+			override: map[string]string{"traefik.foo": "c"},
+			expected: &MergedConfig{Foo: "c", Bar: "b"},
+		},
+		{
+			desc:     "replace slice of values",
+			base:     map[string]string{"traefik.names": "a,b,c"},
+			override: map[string]string{"traefik.names": "d"},
+			expected: &MergedConfig{Names: []string{"d"}},
+		},
+		{
+			desc:     "append slice of values",
+			base:     map[string]string{"traefik.appended": "a,b"},
+			override: map[string]string{"traefik.appended": "c"},
+			expected: &MergedConfig{Appended: []string{"a", "b", "c"}},
+		},
+		{
+			desc:     "merge slice of values by index",
+			base:     map[string]string{"traefik.indexed": "a,b,c"},
+			override: map[string]string{"traefik.indexed": "d"},
+			expected: &MergedConfig{Indexed: []string{"d", "b", "c"}},
+		},
+		{
+			desc:     "default slice strategy",
+			base:     map[string]string{"traefik.names": "a,b"},
+			override: map[string]string{"traefik.names": "c"},
+			opts:     MergeOpts{SliceStrategy: MergeAppend},
+			expected: &MergedConfig{Names: []string{"a", "b", "c"}},
+		},
+		{
+			desc: "replace slice of structs",
+			base: map[string]string{
+				"traefik.servers[0].url":    "a",
+				"traefik.servers[0].weight": "1",
+				"traefik.servers[1].url":    "b",
+			},
+			override: map[string]string{"traefik.servers[0].url": "c"},
+			expected: &MergedConfig{Servers: []MergedServer{{URL: "c"}}},
+		},
+		{
+			desc: "merge slice of structs by index",
+			base: map[string]string{
+				"traefik.servers[0].url":    "a",
+				"traefik.servers[0].weight": "1",
+				"traefik.servers[1].url":    "b",
+			},
+			override: map[string]string{"traefik.servers[0].url": "c"},
+			opts:     MergeOpts{SliceStrategy: MergeIndex},
+			expected: &MergedConfig{Servers: []MergedServer{{URL: "c", Weight: 1}, {URL: "b"}}},
+		},
+		{
+			desc:     "append slice of structs",
+			base:     map[string]string{"traefik.backends[0].url": "a", "traefik.backends[1].url": "b"},
+			override: map[string]string{"traefik.backends[0].url": "c"},
+			expected: &MergedConfig{Backends: []MergedServer{{URL: "a"}, {URL: "b"}, {URL: "c"}}},
+		},
+		{
+			desc: "deep merge map",
+			base: map[string]string{
+				"traefik.routes.foo.url":    "a",
+				"traefik.routes.foo.weight": "1",
+				"traefik.routes.bar.url":    "b",
+			},
+			override: map[string]string{"traefik.routes.foo.url": "c", "traefik.routes.baz.url": "d"},
+			expected: &MergedConfig{Routes: map[string]*MergedServer{
+				"foo": {URL: "c", Weight: 1},
+				"bar": {URL: "b"},
+				"baz": {URL: "d"},
+			}},
+		},
+		{
+			desc:     "replace map",
+			base:     map[string]string{"traefik.headers.foo": "a", "traefik.headers.bar": "b"},
+			override: map[string]string{"traefik.headers.baz": "c"},
+			expected: &MergedConfig{Headers: map[string]string{"baz": "c"}},
+		},
+		{
+			desc:     "default map strategy",
+			base:     map[string]string{"traefik.routes.foo.url": "a"},
+			override: map[string]string{"traefik.routes.bar.url": "b"},
+			opts:     MergeOpts{MapStrategy: MergeReplace},
+			expected: &MergedConfig{Routes: map[string]*MergedServer{"bar": {URL: "b"}}},
+		},
+		{
+			desc:     "disabled node removes the subtree",
+			base:     map[string]string{"traefik.foo": "a", "traefik.health.path": "/health"},
+			override: map[string]string{"traefik.health": "false"},
+			expected: &MergedConfig{Foo: "a"},
+		},
+		{
+			desc:     "enabled node",
+			base:     map[string]string{"traefik.health.path": "/health"},
+			override: map[string]string{"traefik.health": "true"},
+			expected: &MergedConfig{Health: &MergedHealth{Path: "/health"}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			base := decodeMergedNode(t, test.base)
+			override := decodeMergedNode(t, test.override)
+
+			node, err := MergeNodes(base, override, test.opts)
+			require.NoError(t, err)
+
+			element := &MergedConfig{}
+			err = Fill(element, node, FillerOpts{AllowSliceAsStruct: true})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, element)
+
+			// the given trees are not modified.
+			assert.Equal(t, decodeMergedNode(t, test.base), base)
+			assert.Equal(t, decodeMergedNode(t, test.override), override)
+		})
+	}
+}
+
+func TestMergeNodes_untyped(t *testing.T) {
+	base := &Node{
+		Name: "traefik",
+		Children: []*Node{
+			{Name: "foo", Value: "a", Source: &Source{Kind: SourceFile}},
+			{Name: "bar", Children: []*Node{
+				{Name: "[0]", Children: []*Node{{Name: "url", Value: "b"}}},
+				{Name: "[1]", Children: []*Node{{Name: "url", Value: "c"}}},
+			}},
+		},
+	}
+
+	override := &Node{
+		Name: "traefik",
+		Children: []*Node{
+			{Name: "Foo", Value: "d", Source: &Source{Kind: SourceFlag}},
+			{Name: "bar", Children: []*Node{
+				{Name: "[0]", Children: []*Node{{Name: "url", Value: "e"}}},
+			}},
+			{Name: "baz", Value: "f"},
+		},
+	}
+
+	node, err := MergeNodes(base, override, MergeOpts{})
+	require.NoError(t, err)
+
+	expected := &Node{
+		Name: "traefik",
+		Children: []*Node{
+			{Name: "foo", Value: "d", Source: &Source{Kind: SourceFlag}},
+			{Name: "bar", Children: []*Node{
+				{Name: "[0]", Children: []*Node{{Name: "url", Value: "e"}}},
+			}},
+			{Name: "baz", Value: "f"},
+		},
+	}
+	assert.Equal(t, expected, node)
+}
+
+func TestMergeNodes_sensitive(t *testing.T) {
+	base := &Node{
+		Name: "traefik",
+		Children: []*Node{
+			{Name: "foo", Value: "a", Sensitive: true},
+			{Name: "bar", Value: "b"},
+		},
+	}
+
+	override := &Node{
+		Name: "traefik",
+		Children: []*Node{
+			{Name: "foo", Value: "c"},
+			{Name: "bar", Value: "d", Sensitive: true},
+		},
+	}
+
+	node, err := MergeNodes(base, override, MergeOpts{})
+	require.NoError(t, err)
+
+	expected := &Node{
+		Name: "traefik",
+		Children: []*Node{
+			{Name: "foo", Value: "c"},
+			{Name: "bar", Value: "d", Sensitive: true},
+		},
+	}
+	assert.Equal(t, expected, node)
+}
+
+func TestMergeNodes_nil(t *testing.T) {
+	node := &Node{Name: "traefik", Children: []*Node{{Name: "foo", Value: "a"}}}
+
+	merged, err := MergeNodes(nil, node, MergeOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, node, merged)
+
+	merged, err = MergeNodes(node, nil, MergeOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, node, merged)
+}
+
+func TestMergeNodes_rawValueCopy(t *testing.T) {
+	base := &Node{Name: "traefik", Children: []*Node{
+		{Name: "plugins", RawValue: map[string]interface{}{
+			"foo": map[string]interface{}{"a": "1", "list": []interface{}{"x"}},
+			"bar": map[string]interface{}{"b": "2"},
+		}},
+	}}
+
+	override := &Node{Name: "traefik", Children: []*Node{
+		{Name: "plugins", RawValue: map[string]interface{}{
+			"foo": map[string]interface{}{"c": "3"},
+			"baz": map[string]interface{}{"d": "4"},
+		}},
+	}}
+
+	merged, err := MergeNodes(base, override, MergeOpts{})
+	require.NoError(t, err)
+
+	raw := merged.Children[0].RawValue.(map[string]interface{})
+	raw["foo"].(map[string]interface{})["a"] = "changed"
+	raw["foo"].(map[string]interface{})["list"].([]interface{})[0] = "changed"
+	raw["bar"].(map[string]interface{})["b"] = "changed"
+	raw["baz"].(map[string]interface{})["d"] = "changed"
+
+	assert.Equal(t, map[string]interface{}{
+		"foo": map[string]interface{}{"a": "1", "list": []interface{}{"x"}},
+		"bar": map[string]interface{}{"b": "2"},
+	}, base.Children[0].RawValue)
+	assert.Equal(t, map[string]interface{}{
+		"foo": map[string]interface{}{"c": "3"},
+		"baz": map[string]interface{}{"d": "4"},
+	}, override.Children[0].RawValue)
+
+	// the clones don't share the raw values either.
+	clone := cloneNode(base)
+	clone.Children[0].RawValue.(map[string]interface{})["bar"].(map[string]interface{})["b"] = "changed"
+	assert.Equal(t, "2", base.Children[0].RawValue.(map[string]interface{})["bar"].(map[string]interface{})["b"])
+}
+
+func TestMergeNodes_invalidStrategy(t *testing.T) {
+	element := &struct {
+		Routes map[string]string `merge:"append"`
+	}{}
+
+	base := &Node{Name: "traefik", Children: []*Node{{Name: "routes", Children: []*Node{{Name: "foo", Value: "a"}}}}}
+	require.NoError(t, AddMetadata(element, base, MetadataOpts{TagName: TagLabel}))
+
+	_, err := MergeNodes(base, base, MergeOpts{})
+	assert.EqualError(t, err, `traefik.routes: invalid merge strategy "append" (kind map)`)
+}
+
+func decodeMergedNode(t *testing.T, labels map[string]string) *Node {
+	t.Helper()
+
+	node, err := DecodeToNode(labels, DefaultRootName)
+	require.NoError(t, err)
+
+	err = AddMetadata(&MergedConfig{}, node, MetadataOpts{TagName: TagLabel, AllowSliceAsStruct: true})
+	require.NoError(t, err)
+
+	return node
+}
@@ -1,6 +1,10 @@
 package parser
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
 
 // DefaultRootName is the default name of the root node and the prefix of element name from the resources.
 const DefaultRootName = "traefik"
@@ -9,6 +13,8 @@ const DefaultRootName = "traefik"
 const MapNamePlaceholder = "<name>"
 
 // Node is a label node.
+// The values of the Sensitive nodes (ex: the content of a secret file) are redacted when they are reported,
+// whatever the tags of their fields (see TagSensitive).
 type Node struct {
 	Name        string            `json:"name"`
 	Description string            `json:"description,omitempty"`
@@ -16,7 +22,226 @@ type Node struct {
 	Value       string            `json:"value,omitempty"`
 	RawValue    interface{}       `json:"rawValue,omitempty"`
 	Disabled    bool              `json:"disabled,omitempty"`
+	Sensitive   bool              `json:"sensitive,omitempty"`
 	Kind        reflect.Kind      `json:"kind,omitempty"`
 	Tag         reflect.StructTag `json:"tag,omitempty"`
+	Source      *Source           `json:"source,omitempty"`
 	Children    []*Node           `json:"children,omitempty"`
 }
+
+// SourceKind is the kind of source of a configuration value.
+type SourceKind string
+
+// Source kinds.
+const (
+	SourceLabel SourceKind = "label"
+	SourceFile  SourceKind = "file"
+	SourceEnv   SourceKind = "env"
+	SourceFlag  SourceKind = "flag"
+)
+
+// Source describes where a configuration value comes from.
+type Source struct {
+	Kind SourceKind `json:"kind"`
+	// Name is the label key, the file path, the environment variable name, or the flag name.
+	Name string `json:"name,omitempty"`
+	// Line and Column are the position of the value in a file, starting at 1 (0 if unknown).
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Index is the position of the flag in the arguments, starting at 1 (0 if unknown).
+	Index int `json:"index,omitempty"`
+}
+
+func (s *Source) String() string {
+	if s == nil {
+		return ""
+	}
+
+	switch s.Kind {
+	case SourceFile:
+		name := s.Name
+		if name == "" {
+			name = string(s.Kind)
+		}
+
+		switch {
+		case s.Line > 0 && s.Column > 0:
+			return fmt.Sprintf("%s:%d:%d", name, s.Line, s.Column)
+		case s.Line > 0:
+			return fmt.Sprintf("%s:%d", name, s.Line)
+		default:
+			return name
+		}
+
+	case SourceFlag:
+		if s.Index > 0 {
+			return fmt.Sprintf("flag %s (argument %d)", s.Name, s.Index)
+		}
+		return "flag " + s.Name
+
+	default:
+		if s.Name == "" {
+			return string(s.Kind)
+		}
+		return string(s.Kind) + " " + s.Name
+	}
+}
+
+// SetSources sets the source of the nodes of the tree, from the leaves to the root.
+// The source of a node is the one returned by getSource for the path of the node (ex: traefik.foo[0].bar),
+// or, if nil, the source of its first child having a source.
+// The previous sources of the nodes are replaced.
+func SetSources(node *Node, getSource func(path string) *Source) {
+	if node == nil {
+		return
+	}
+
+	setSources(node, node.Name, getSource)
+}
+
+func setSources(node *Node, path string, getSource func(path string) *Source) {
+	for _, child := range node.Children {
+		setSources(child, childPath(path, child.Name), getSource)
+	}
+
+	node.Source = getSource(path)
+	if node.Source != nil {
+		return
+	}
+
+	for _, child := range node.Children {
+		if child.Source != nil {
+			node.Source = child.Source
+			return
+		}
+	}
+}
+
+// Get returns the descendant node located at path, or nil if it doesn't exist.
+// The path is relative to the node, and is case-insensitive (ex: "entryPoints.web.address", "servers[0].url").
+func (n *Node) Get(path string) *Node {
+	segments, err := splitPath(path)
+	if err != nil {
+		return nil
+	}
+
+	node := n
+	for _, segment := range segments {
+		if node = containsNode(node.Children, segment); node == nil {
+			return nil
+		}
+	}
+
+	return node
+}
+
+// Set sets the value of the descendant node located at path, the missing nodes are created.
+// The path is relative to the node, and is case-insensitive (ex: "entryPoints.web.address", "servers[0].url").
+func (n *Node) Set(path, value string) error {
+	segments, err := splitPath(path)
+	if err != nil {
+		return err
+	}
+
+	node := n
+	for _, segment := range segments {
+		child := containsNode(node.Children, segment)
+		if child == nil {
+			child = &Node{Name: segment}
+			node.Children = append(node.Children, child)
+		}
+
+		node = child
+	}
+
+	node.Value = value
+
+	return nil
+}
+
+// Delete removes the descendant node located at path, and its children.
+// It returns false if the node doesn't exist.
+func (n *Node) Delete(path string) bool {
+	segments, err := splitPath(path)
+	if err != nil || len(segments) == 0 {
+		return false
+	}
+
+	parent := n.Get(strings.Join(segments[:len(segments)-1], "."))
+	if parent == nil {
+		return false
+	}
+
+	for i, child := range parent.Children {
+		if strings.EqualFold(child.Name, segments[len(segments)-1]) {
+			parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// SensitivePaths returns the paths of the sensitive nodes, relative to the node (see Get),
+// i.e. the nodes including a resolved secret or the content of a secret file, and the nodes of the sensitive fields (see TagSensitive).
+// The paths can be redacted by the encoders (see EncoderToNodeOpts.SensitivePaths).
+func (n *Node) SensitivePaths() []string {
+	if n == nil {
+		return nil
+	}
+
+	return getSensitivePaths(n, "", nil)
+}
+
+// Walk calls fn for the node and each of its descendants, depth-first, with their path relative to the node
+// (ex: "", "entryPoints", "entryPoints.web", "servers[0]").
+// The walk stops at the first error, which is returned.
+func (n *Node) Walk(fn func(path string, n *Node) error) error {
+	return walk(n, "", fn)
+}
+
+func walk(node *Node, path string, fn func(path string, n *Node) error) error {
+	if err := fn(path, node); err != nil {
+		return err
+	}
+
+	for _, child := range node.Children {
+		if err := walk(child, childPath(path, child.Name), fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitPath splits a path into the names of the nodes (ex: "servers[0].url" -> "servers", "[0]", "url").
+func splitPath(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		name, index, found := strings.Cut(part, "[")
+		if name == "" && !found {
+			return nil, fmt.Errorf("invalid path: %s", path)
+		}
+
+		if name != "" {
+			segments = append(segments, name)
+		}
+
+		for found {
+			var rest string
+			index, rest, found = strings.Cut(index, "[")
+			if !strings.HasSuffix(index, "]") || len(index) < 2 {
+				return nil, fmt.Errorf("invalid path: %s", path)
+			}
+
+			segments = append(segments, "["+index)
+			index = rest
+		}
+	}
+
+	return segments, nil
+}
@@ -0,0 +1,282 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_String(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		source   *Source
+		expected string
+	}{
+		{
+			desc: "nil",
+		},
+		{
+			desc:     "label",
+			source:   &Source{Kind: SourceLabel, Name: "traefik.foo"},
+			expected: "label traefik.foo",
+		},
+		{
+			desc:     "env",
+			source:   &Source{Kind: SourceEnv, Name: "TRAEFIK_FOO"},
+			expected: "env TRAEFIK_FOO",
+		},
+		{
+			desc:     "flag",
+			source:   &Source{Kind: SourceFlag, Name: "--foo", Index: 2},
+			expected: "flag --foo (argument 2)",
+		},
+		{
+			desc:     "file",
+			source:   &Source{Kind: SourceFile, Name: "traefik.toml"},
+			expected: "traefik.toml",
+		},
+		{
+			desc:     "file with position",
+			source:   &Source{Kind: SourceFile, Name: "traefik.toml", Line: 3, Column: 5},
+			expected: "traefik.toml:3:5",
+		},
+		{
+			desc:     "file content",
+			source:   &Source{Kind: SourceFile, Line: 3},
+			expected: "file:3",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, test.source.String())
+		})
+	}
+}
+
+func TestSetSources(t *testing.T) {
+	labels := map[string]string{
+		"traefik.foo.bar":      "a",
+		"traefik.foo.baz":      "b",
+		"traefik.fii[0].field": "c",
+	}
+
+	node, err := DecodeToNode(labels, DefaultRootName)
+	require.NoError(t, err)
+
+	SetSources(node, func(path string) *Source {
+		if _, ok := labels[path]; ok {
+			return &Source{Kind: SourceLabel, Name: path}
+		}
+		return nil
+	})
+
+	sources := map[string]string{}
+
+	var walk func(n *Node, path string)
+	walk = func(n *Node, path string) {
+		sources[path] = n.Source.String()
+		for _, child := range n.Children {
+			walk(child, childPath(path, child.Name))
+		}
+	}
+	walk(node, node.Name)
+
+	expected := map[string]string{
+		"traefik":              "label traefik.fii[0].field",
+		"traefik.fii":          "label traefik.fii[0].field",
+		"traefik.fii[0]":       "label traefik.fii[0].field",
+		"traefik.fii[0].field": "label traefik.fii[0].field",
+		"traefik.foo":          "label traefik.foo.bar",
+		"traefik.foo.bar":      "label traefik.foo.bar",
+		"traefik.foo.baz":      "label traefik.foo.baz",
+	}
+	assert.Equal(t, expected, sources)
+}
+
+func TestDecode_source(t *testing.T) {
+	element := &struct {
+		Foo struct {
+			Bar int
+		}
+	}{}
+
+	err := Decode(map[string]string{"traefik.Foo.Bar": "a"}, element, DefaultRootName)
+	require.Error(t, err)
+
+	dErrs := AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+
+	assert.Equal(t, &Source{Kind: SourceLabel, Name: "traefik.Foo.Bar"}, dErrs[0].Source)
+}
+
+func TestAddMetadata_keepSources(t *testing.T) {
+	element := &struct {
+		Foo struct {
+			Bar int
+		}
+	}{}
+
+	node, err := DecodeToNode(map[string]string{"traefik.foo.bar": "1"}, DefaultRootName)
+	require.NoError(t, err)
+
+	SetSources(node, func(path string) *Source {
+		return &Source{Kind: SourceLabel, Name: path}
+	})
+
+	err = AddMetadata(element, node, MetadataOpts{TagName: TagLabel})
+	require.NoError(t, err)
+
+	assert.Equal(t, &Source{Kind: SourceLabel, Name: "traefik.foo.bar"}, node.Children[0].Children[0].Source)
+}
+
+func newPathNode(t *testing.T) *Node {
+	t.Helper()
+
+	node, err := DecodeToNode(map[string]string{
+		"traefik.entryPoints.web.address": ":80",
+		"traefik.servers[0].url":          "http://a",
+		"traefik.servers[1].url":          "http://b",
+	}, DefaultRootName)
+	require.NoError(t, err)
+
+	return node
+}
+
+func TestNode_Get(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		path     string
+		expected string
+		notFound bool
+	}{
+		{
+			desc:     "leaf",
+			path:     "entryPoints.web.address",
+			expected: ":80",
+		},
+		{
+			desc:     "case-insensitive",
+			path:     "entrypoints.WEB.Address",
+			expected: ":80",
+		},
+		{
+			desc:     "slice element",
+			path:     "servers[1].url",
+			expected: "http://b",
+		},
+		{
+			desc:     "unknown node",
+			path:     "servers[2].url",
+			notFound: true,
+		},
+		{
+			desc:     "invalid path",
+			path:     "servers[.url",
+			notFound: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			node := newPathNode(t).Get(test.path)
+
+			if test.notFound {
+				assert.Nil(t, node)
+				return
+			}
+
+			require.NotNil(t, node)
+			assert.Equal(t, test.expected, node.Value)
+		})
+	}
+}
+
+func TestNode_Get_self(t *testing.T) {
+	node := newPathNode(t)
+
+	assert.Same(t, node, node.Get(""))
+}
+
+func TestNode_Set(t *testing.T) {
+	node := newPathNode(t)
+
+	require.NoError(t, node.Set("entryPoints.web.address", ":8080"))
+	require.NoError(t, node.Set("servers[2].url", "http://c"))
+	require.NoError(t, node.Set("entryPoints.websecure.address", ":443"))
+
+	assert.Equal(t, ":8080", node.Get("entryPoints.web.address").Value)
+	assert.Equal(t, "http://c", node.Get("servers[2].url").Value)
+	assert.Equal(t, ":443", node.Get("entryPoints.websecure.address").Value)
+
+	assert.Len(t, node.Get("servers").Children, 3)
+
+	assert.EqualError(t, node.Set("servers[].url", "http://c"), "invalid path: servers[].url")
+	assert.EqualError(t, node.Set("servers..url", "http://c"), "invalid path: servers..url")
+}
+
+func TestNode_Delete(t *testing.T) {
+	node := newPathNode(t)
+
+	assert.True(t, node.Delete("servers[0]"))
+	assert.True(t, node.Delete("entryPoints.web.address"))
+	assert.False(t, node.Delete("entryPoints.web.address"))
+	assert.False(t, node.Delete("foo.bar"))
+	assert.False(t, node.Delete(""))
+
+	assert.Nil(t, node.Get("servers[0]"))
+	assert.Equal(t, "http://b", node.Get("servers[1].url").Value)
+	assert.Empty(t, node.Get("entryPoints.web").Children)
+}
+
+func TestNode_Walk(t *testing.T) {
+	node := newPathNode(t)
+
+	var paths []string
+	err := node.Walk(func(path string, n *Node) error {
+		paths = append(paths, path)
+		return nil
+	})
+	require.NoError(t, err)
+
+	expected := []string{
+		"",
+		"entryPoints",
+		"entryPoints.web",
+		"entryPoints.web.address",
+		"servers",
+		"servers[0]",
+		"servers[0].url",
+		"servers[1]",
+		"servers[1].url",
+	}
+	assert.Equal(t, expected, paths)
+
+	for _, path := range paths {
+		assert.NotNil(t, node.Get(path), path)
+	}
+}
+
+func TestNode_Walk_error(t *testing.T) {
+	node := newPathNode(t)
+
+	var paths []string
+	err := node.Walk(func(path string, n *Node) error {
+		paths = append(paths, path)
+		if path == "entryPoints" {
+			return errors.New("stop")
+		}
+		return nil
+	})
+	require.EqualError(t, err, "stop")
+
+	assert.Equal(t, []string{"", "entryPoints"}, paths)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -11,15 +12,65 @@ import (
 type MetadataOpts struct {
 	TagName            string
 	AllowSliceAsStruct bool
+	// AggregateErrors allows to return all the errors (DecodeErrors), instead of stopping at the first error.
+	// The invalid nodes are removed from the tree.
+	AggregateErrors bool
+	// UnknownKeys is the policy applied to the nodes which don't match any field (strict if not set).
+	// With the UnknownKeysWarn and UnknownKeysIgnore policies, the unknown nodes are removed from the tree,
+	// and with the UnknownKeysWarn policy, an UnknownKeysError is returned.
+	UnknownKeys UnknownKeysPolicy
+	// OnDeprecation is called for each node using a deprecated name of a field (see TagAlias).
+	OnDeprecation func(Deprecation)
+}
+
+// Deprecation describes the use of a deprecated name of a field (see TagAlias).
+type Deprecation struct {
+	// OldPath is the path using the deprecated name.
+	OldPath string
+	// NewPath is the path using the current name.
+	NewPath string
+	// Source is the source of the value: label, flag, environment variable name, or file path.
+	Source string
 }
 
 // AddMetadata adds metadata such as type, inferred from element, to a node.
 func AddMetadata(element interface{}, node *Node, opts MetadataOpts) error {
-	return metadata{MetadataOpts: opts}.Add(element, node)
+	m := metadata{MetadataOpts: opts, state: &metadataState{}}
+
+	err := redactSensitiveErrors(m.Add(element, node), node)
+
+	m.notifyDeprecations(node)
+
+	if err != nil {
+		m.completeSuggestions(node, err)
+		return err
+	}
+
+	if opts.UnknownKeys == UnknownKeysWarn && len(m.state.unknownKeys) > 0 {
+		sort.Strings(m.state.unknownKeys)
+		return &UnknownKeysError{Keys: m.state.unknownKeys}
+	}
+
+	return nil
 }
 
 type metadata struct {
 	MetadataOpts
+
+	state *metadataState
+}
+
+// metadataState holds the information collected while browsing the nodes.
+type metadataState struct {
+	unknownKeys  []string
+	deprecations []deprecatedKey
+}
+
+// deprecatedKey is a key using a deprecated name.
+type deprecatedKey struct {
+	path   string
+	parent *Node
+	name   string
 }
 
 // Add adds metadata such as type, inferred from element, to a node.
@@ -39,19 +90,52 @@ func (m metadata) Add(element interface{}, node *Node) error {
 	rootType := reflect.TypeOf(element)
 	node.Kind = rootType.Kind()
 
-	return m.browseChildren(rootType, node)
+	return m.browseChildren(rootType, node, node.Name)
 }
 
-func (m metadata) browseChildren(fType reflect.Type, node *Node) error {
+// browseChildren adds metadata to the children of the node located at path.
+func (m metadata) browseChildren(fType reflect.Type, node *Node, path string) error {
+	var errs DecodeErrors
+
+	children := node.Children[:0]
 	for _, child := range node.Children {
-		if err := m.add(fType, child); err != nil {
+		p := childPath(path, child.Name)
+
+		err := m.add(fType, child, p)
+
+		m.checkDeprecated(node, child, p)
+
+		if err == nil {
+			children = append(children, child)
+			continue
+		}
+
+		if errors.Is(err, ErrFieldNotFound) && m.skipUnknownKey(p) {
+			continue
+		}
+
+		err = m.wrapError(err, fType, node, child, p)
+
+		if !m.AggregateErrors {
 			return err
 		}
+
+		dErrs := AsDecodeErrors(err)
+		errs = append(errs, dErrs...)
+
+		// only the child itself is removed, not the parent of an invalid node.
+		if !hasDecodeError(dErrs, p) {
+			children = append(children, child)
+		}
 	}
-	return nil
+
+	node.Children = children
+
+	return errs.errorOrNil()
 }
 
-func (m metadata) add(rootType reflect.Type, node *Node) error {
+// add adds metadata to the node located at path.
+func (m metadata) add(rootType reflect.Type, node *Node, path string) error {
 	rType := rootType
 	if rootType.Kind() == reflect.Pointer {
 		rType = rootType.Elem()
@@ -75,6 +159,15 @@ func (m metadata) add(rootType reflect.Type, node *Node) error {
 	node.Kind = fType.Kind()
 	node.Tag = field.Tag
 
+	if isCustomTypeOrPtr(fType) {
+		if len(node.Children) > 0 {
+			return fmt.Errorf("%s cannot have children (type %s)", node.Name, fType)
+		}
+
+		node.Disabled = field.Tag.Get(m.TagName) == "-"
+		return nil
+	}
+
 	if fType.Kind() == reflect.Struct || fType.Kind() == reflect.Pointer && fType.Elem().Kind() == reflect.Struct ||
 		fType.Kind() == reflect.Map {
 		if len(node.Children) == 0 && !(field.Tag.Get(m.TagName) == TagLabelAllowEmpty || field.Tag.Get(m.TagName) == "-") {
@@ -91,7 +184,7 @@ func (m metadata) add(rootType reflect.Type, node *Node) error {
 	}
 
 	if fType.Kind() == reflect.Struct || fType.Kind() == reflect.Pointer && fType.Elem().Kind() == reflect.Struct {
-		return m.browseChildren(fType, node)
+		return m.browseChildren(fType, node, path)
 	}
 
 	if fType.Kind() == reflect.Map {
@@ -100,41 +193,114 @@ func (m metadata) add(rootType reflect.Type, node *Node) error {
 			return nil
 		}
 
+		var errs DecodeErrors
+
 		for _, child := range node.Children {
 			// elem is a map entry value type
 			elem := fType.Elem()
 			child.Kind = elem.Kind()
 
-			if elem.Kind() == reflect.Map || elem.Kind() == reflect.Struct ||
-				(elem.Kind() == reflect.Pointer && elem.Elem().Kind() == reflect.Struct) {
-				if err = m.browseChildren(elem, child); err != nil {
-					return err
+			if !isCustomTypeOrPtr(elem) && (elem.Kind() == reflect.Map || elem.Kind() == reflect.Struct ||
+				(elem.Kind() == reflect.Pointer && elem.Elem().Kind() == reflect.Struct)) {
+				if err = m.browseChildren(elem, child, childPath(path, child.Name)); err != nil {
+					if !m.AggregateErrors {
+						return err
+					}
+
+					errs = append(errs, AsDecodeErrors(err)...)
 				}
 			}
 		}
-		return nil
+		return errs.errorOrNil()
 	}
 
 	if fType.Kind() == reflect.Slice {
 		if m.AllowSliceAsStruct && field.Tag.Get(TagLabelSliceAsStruct) != "" {
-			return m.browseChildren(fType.Elem(), node)
+			return m.browseChildren(fType.Elem(), node, path)
 		}
 
+		var errs DecodeErrors
+
 		for _, ch := range node.Children {
 			ch.Kind = fType.Elem().Kind()
-			if err = m.browseChildren(fType.Elem(), ch); err != nil {
-				return err
+			if err = m.browseChildren(fType.Elem(), ch, childPath(path, ch.Name)); err != nil {
+				if !m.AggregateErrors {
+					return err
+				}
+
+				errs = append(errs, AsDecodeErrors(err)...)
 			}
 		}
-		return nil
+		return errs.errorOrNil()
 	}
 
 	return fmt.Errorf("invalid node %s: %v", node.Name, fType.Kind())
 }
 
+// wrapError wraps the error related to the child located at path into a DecodeError.
+// If the child is unknown, the closest field name of fType is suggested.
+func (m metadata) wrapError(err error, fType reflect.Type, node, child *Node, path string) error {
+	var dErr *DecodeError
+	if errors.As(err, &dErr) {
+		return err
+	}
+
+	dErr = &DecodeError{Path: path, Value: child.Value, Source: child.Source, Err: err}
+
+	if errors.Is(err, ErrFieldNotFound) {
+		dErr.Suggestion = m.suggestFieldName(fType, child.Name)
+		dErr.parent = node
+	}
+
+	return dErr
+}
+
+// skipUnknownKey records the unknown key located at path, and reports whether it must be skipped.
+func (m metadata) skipUnknownKey(path string) bool {
+	if m.UnknownKeys != UnknownKeysWarn && m.UnknownKeys != UnknownKeysIgnore {
+		return false
+	}
+
+	if m.state != nil {
+		m.state.unknownKeys = append(m.state.unknownKeys, path)
+	}
+
+	return true
+}
+
+// checkDeprecated records the child located at path, if it uses a deprecated name of a field.
+func (m metadata) checkDeprecated(node, child *Node, path string) {
+	if m.state == nil || m.OnDeprecation == nil || child.FieldName == "" {
+		return
+	}
+
+	name := m.getName(child.FieldName, child.Tag)
+	if strings.EqualFold(name, child.Name) {
+		return
+	}
+
+	m.state.deprecations = append(m.state.deprecations, deprecatedKey{path: path, parent: node, name: name})
+}
+
+// notifyDeprecations calls the deprecation callback for each node using a deprecated name.
+func (m metadata) notifyDeprecations(root *Node) {
+	if m.state == nil || m.OnDeprecation == nil {
+		return
+	}
+
+	for _, key := range m.state.deprecations {
+		parentPath, ok := m.getCanonicalPath(root, key.parent, "")
+		if !ok {
+			continue
+		}
+
+		m.OnDeprecation(Deprecation{OldPath: key.path, NewPath: childPath(parentPath, key.name)})
+	}
+}
+
 func (m metadata) findTypedField(rType reflect.Type, node *Node) (reflect.StructField, error) {
 	if rType.Kind() != reflect.Struct {
-		return reflect.StructField{}, fmt.Errorf("field not found, node: %s", node.Name)
+		return reflect.StructField{}, fmt.Errorf("%w, node: %s", ErrFieldNotFound, node.Name)
 	}
 
 	for i := 0; i < rType.NumField(); i++ {
@@ -156,14 +322,14 @@ func (m metadata) findTypedField(rType reflect.Type, node *Node) (reflect.Struct
 				}
 			}
 
-			if strings.EqualFold(fieldName, node.Name) {
+			if strings.EqualFold(fieldName, node.Name) || isAlias(cField, node.Name) {
 				node.FieldName = cField.Name
 				return cField, nil
 			}
 		}
 	}
 
-	return reflect.StructField{}, fmt.Errorf("field not found, node: %s", node.Name)
+	return reflect.StructField{}, fmt.Errorf("%w, node: %s", ErrFieldNotFound, node.Name)
 }
 
 // IsExported reports whether f is exported.
@@ -172,10 +338,36 @@ func IsExported(f reflect.StructField) bool {
 	return f.PkgPath == ""
 }
 
+// GetAliases returns the deprecated names of f (see TagAlias).
+func GetAliases(f reflect.StructField) []string {
+	var aliases []string
+	for _, alias := range strings.Split(f.Tag.Get(TagAlias), ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return aliases
+}
+
+func isAlias(f reflect.StructField, name string) bool {
+	for _, alias := range GetAliases(f) {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func isSupportedType(field reflect.StructField) error {
 	fType := field.Type
 
 	if fType.Kind() == reflect.Slice {
+		if IsCustomType(fType.Elem()) {
+			return nil
+		}
+
 		switch fType.Elem().Kind() {
 		case reflect.String,
 			reflect.Bool,
@@ -220,6 +412,11 @@ func addRawValue(node *Node) {
 		node.RawValue = nodeToRawMap(node)
 	}
 
+	// the raw value includes the values of the sensitive children.
+	for _, child := range node.Children {
+		node.Sensitive = node.Sensitive || len(getSensitivePaths(child, child.Name, nil)) > 0
+	}
+
 	node.Children = nil
 }
 
@@ -1061,7 +1061,7 @@ func TestAddMetadata(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			err := metadata{MetadataOpts{TagName: TagLabel, AllowSliceAsStruct: true}}.Add(test.structure, test.tree)
+			err := metadata{MetadataOpts: MetadataOpts{TagName: TagLabel, AllowSliceAsStruct: true}}.Add(test.structure, test.tree)
 
 			if test.expected.error {
 				assert.Error(t, err)
@@ -1182,3 +1182,56 @@ func Test_nodeToRawMap(t *testing.T) {
 		})
 	}
 }
+
+func TestAddMetadata_aliases(t *testing.T) {
+	type EntryPoint struct {
+		Address string `alias:"addr,listen"`
+	}
+
+	type Configuration struct {
+		EntryPoints map[string]*EntryPoint `alias:"endpoints"`
+		Name        string
+	}
+
+	labels := map[string]string{
+		"traefik.endpoints.web.addr":           ":80",
+		"traefik.entrypoints.websecure.listen": ":443",
+		"traefik.name":                         "foo",
+	}
+
+	var deprecations []Deprecation
+	opts := DecodeOpts{
+		OnDeprecation: func(d Deprecation) {
+			deprecations = append(deprecations, d)
+		},
+	}
+
+	element := &Configuration{}
+	err := DecodeWithOpts(labels, element, DefaultRootName, opts)
+	require.NoError(t, err)
+
+	expected := &Configuration{
+		EntryPoints: map[string]*EntryPoint{
+			"web":       {Address: ":80"},
+			"websecure": {Address: ":443"},
+		},
+		Name: "foo",
+	}
+	assert.Equal(t, expected, element)
+
+	expectedDeprecations := []Deprecation{
+		{OldPath: "traefik.endpoints", NewPath: "traefik.entryPoints", Source: "label"},
+		{OldPath: "traefik.endpoints.web.addr", NewPath: "traefik.entryPoints.web.address", Source: "label"},
+		{OldPath: "traefik.entrypoints.websecure.listen", NewPath: "traefik.entryPoints.websecure.address", Source: "label"},
+	}
+	assert.ElementsMatch(t, expectedDeprecations, deprecations)
+
+	labels, err = Encode(element, DefaultRootName)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"traefik.EntryPoints.web.Address":       ":80",
+		"traefik.EntryPoints.websecure.Address": ":443",
+		"traefik.Name":                          "foo",
+	}, labels)
+}
@@ -1,6 +1,45 @@
 // Package parser implements decoding and encoding between a flat map of labels and a typed Configuration.
 package parser
 
+import (
+	"errors"
+)
+
+// UnknownKeysPolicy defines how the keys which don't match any field are handled.
+type UnknownKeysPolicy string
+
+// Unknown keys policies.
+const (
+	// UnknownKeysStrict makes the decoding fail on unknown keys.
+	UnknownKeysStrict UnknownKeysPolicy = "strict"
+	// UnknownKeysWarn skips the unknown keys, and returns them in an UnknownKeysError once the element is filled.
+	UnknownKeysWarn UnknownKeysPolicy = "warn"
+	// UnknownKeysIgnore silently skips the unknown keys.
+	UnknownKeysIgnore UnknownKeysPolicy = "ignore"
+)
+
+// DecodeOpts Options for the decoding.
+type DecodeOpts struct {
+	// AggregateErrors allows to fill all the valid fields and to return all the errors (DecodeErrors),
+	// instead of stopping at the first error.
+	AggregateErrors bool
+	// UnknownKeys is the policy applied to the keys which don't match any field.
+	// If not set, the default behavior of each decoder applies:
+	// the unknown keys make the decoding fail, except the root keys of a file which are skipped.
+	UnknownKeys UnknownKeysPolicy
+	// OnDeprecation is called for each key using a deprecated name of a field (see TagAlias).
+	OnDeprecation func(Deprecation)
+	// Interpolate enables the expansion of the references to the environment variables (${VAR} or ${VAR:-default})
+	// in the values of the files, labels, and flags (see Interpolate).
+	// The missing variables are reported as decode errors, with the path of the related values.
+	Interpolate bool
+	// LookupEnv retrieves the value of the interpolated environment variables (os.LookupEnv if nil).
+	LookupEnv func(string) (string, bool)
+	// Resolve enables the resolution of the references to the secrets (${scheme:reference}) in the values,
+	// by the registered resolvers (see RegisterResolver).
+	Resolve bool
+}
+
 // Decode decodes the given map of labels into the given element.
 // If any filters are present, labels which do not match the filters are skipped.
 // The operation goes through three stages roughly summarized as:
@@ -8,24 +47,124 @@ package parser
 // untyped nodes -> nodes augmented with metadata such as kind (inferred from element)
 // "typed" nodes -> typed element.
 func Decode(labels map[string]string, element interface{}, rootName string, filters ...string) error {
+	return DecodeWithOpts(labels, element, rootName, DecodeOpts{}, filters...)
+}
+
+// DecodeWithOpts decodes the given map of labels into the given element, according to the options.
+// If any filters are present, labels which do not match the filters are skipped.
+func DecodeWithOpts(labels map[string]string, element interface{}, rootName string, opts DecodeOpts, filters ...string) error {
 	node, err := DecodeToNode(labels, rootName, filters...)
 	if err != nil {
 		return err
 	}
 
-	metaOpts := MetadataOpts{TagName: TagLabel, AllowSliceAsStruct: true}
-	err = AddMetadata(element, node, metaOpts)
+	opts.OnDeprecation = withSource(opts.OnDeprecation, "label")
+
+	return setOrigin(DecodeLabelNode(element, node, opts), "label")
+}
+
+// DecodeLabelNode decodes a tree of nodes built from labels (see DecodeToNode) into the given element,
+// according to the options.
+func DecodeLabelNode(element interface{}, node *Node, opts DecodeOpts) error {
+	metaOpts := MetadataOpts{
+		TagName:            TagLabel,
+		AllowSliceAsStruct: true,
+		AggregateErrors:    opts.AggregateErrors,
+		UnknownKeys:        opts.UnknownKeys,
+		OnDeprecation:      opts.OnDeprecation,
+	}
+	fillerOpts := FillerOpts{
+		AllowSliceAsStruct: true,
+		AggregateErrors:    opts.AggregateErrors,
+		Interpolate:        opts.Interpolate,
+		LookupEnv:          opts.LookupEnv,
+		Resolve:            opts.Resolve,
+	}
+
+	return DecodeNode(element, node, metaOpts, fillerOpts)
+}
+
+// DecodeNode adds the metadata to the node, and then populates the fields of the element using the node.
+// When the references are expanded (see FillerOpts.Interpolate and FillerOpts.Resolve),
+// they are expanded before adding the metadata, in a copy of the node.
+// When the errors are aggregated, the valid fields are filled even if the metadata of some nodes are invalid,
+// and all the errors are returned sorted by path.
+// Once the element is filled without errors, it is validated (see Validate).
+// With the UnknownKeysWarn policy, the UnknownKeysError is returned only if there is no other error.
+func DecodeNode(element interface{}, node *Node, metaOpts MetadataOpts, fillerOpts FillerOpts) error {
+	var errs DecodeErrors
+
+	var expandErr error
+	if node != nil && (fillerOpts.Interpolate || fillerOpts.Resolve) {
+		// the expanded values can change the metadata (ex: an "allowEmpty" field enabled by ${ENABLED}).
+		node, expandErr = newFiller(fillerOpts).expand(node)
+		if expandErr != nil && !fillerOpts.AggregateErrors {
+			return expandErr
+		}
+
+		errs = append(errs, AsDecodeErrors(expandErr)...)
+		fillerOpts.Interpolate = false
+		fillerOpts.Resolve = false
+	}
+
+	err := AddMetadata(element, node, metaOpts)
+
+	var unknownErr *UnknownKeysError
+	if errors.As(err, &unknownErr) {
+		err = nil
+	}
+
 	if err != nil {
-		return err
+		dErrs := AsDecodeErrors(err)
+		if !metaOpts.AggregateErrors || len(dErrs) == 0 {
+			return err
+		}
+
+		errs = append(errs, dErrs...)
 	}
 
-	return Fill(element, node, FillerOpts{AllowSliceAsStruct: true})
+	// the element is not filled with the values that failed to be expanded.
+	if expandErr == nil {
+		err = Fill(element, node, fillerOpts)
+		if err != nil {
+			dErrs := AsDecodeErrors(err)
+			if !fillerOpts.AggregateErrors || len(dErrs) == 0 {
+				return err
+			}
+
+			errs = append(errs, dErrs...)
+		}
+	}
+
+	if len(errs) > 0 {
+		sortDecodeErrors(errs)
+		return errs
+	}
+
+	if node != nil {
+		err = Validate(element, node.Name)
+
+		dErrs := AsDecodeErrors(err)
+		if len(dErrs) > 0 && !fillerOpts.AggregateErrors {
+			return dErrs[0]
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if unknownErr != nil {
+		return unknownErr
+	}
+
+	return nil
 }
 
 // Encode converts an element to labels.
 // element -> node (value) -> label (node).
 func Encode(element interface{}, rootName string) (map[string]string, error) {
-	etnOpts := EncoderToNodeOpts{OmitEmpty: true, TagName: TagLabel, AllowSliceAsStruct: true}
+	etnOpts := EncoderToNodeOpts{OmitEmpty: true, TagName: TagLabel, AllowSliceAsStruct: true, Redact: true}
 	node, err := EncodeToNode(element, rootName, etnOpts)
 	if err != nil {
 		return nil, err
@@ -33,3 +172,18 @@ func Encode(element interface{}, rootName string) (map[string]string, error) {
 
 	return EncodeNode(node), nil
 }
+
+// withSource returns a deprecation callback setting the source of the deprecations, if not already set.
+func withSource(fn func(Deprecation), source string) func(Deprecation) {
+	if fn == nil {
+		return nil
+	}
+
+	return func(d Deprecation) {
+		if d.Source == "" {
+			d.Source = source
+		}
+
+		fn(d)
+	}
+}
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"math/big"
+	"net"
+	"net/netip"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -389,3 +392,87 @@ func TestDecode_RawValue(t *testing.T) {
 		})
 	}
 }
+
+type Network struct {
+	Level   LogLevel
+	IP      net.IP
+	Prefix  *netip.Prefix
+	Trusted []netip.Prefix
+	Size    big.Int
+	Levels  map[string]LogLevel
+}
+
+func TestDecode_CustomTypes(t *testing.T) {
+	labels := map[string]string{
+		"traefik.level":       "debug",
+		"traefik.ip":          "10.0.0.1",
+		"traefik.prefix":      "10.0.0.0/8",
+		"traefik.trusted":     "10.0.0.0/8,192.168.0.0/16",
+		"traefik.size":        "123456789012345678901234567890",
+		"traefik.levels.foo":  "warn",
+		"traefik.levels.bar":  "info",
+		"traefik.levels.buzz": "debug",
+	}
+
+	element := &Network{}
+	err := Decode(labels, element, "traefik")
+	require.NoError(t, err)
+
+	size, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	prefix := netip.MustParsePrefix("10.0.0.0/8")
+
+	expected := &Network{
+		Level:   LogLevelDebug,
+		IP:      net.ParseIP("10.0.0.1"),
+		Prefix:  &prefix,
+		Trusted: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")},
+		Size:    *size,
+		Levels:  map[string]LogLevel{"foo": LogLevelWarn, "bar": LogLevelInfo, "buzz": LogLevelDebug},
+	}
+	assert.Equal(t, expected, element)
+
+	encoded, err := Encode(element, "traefik")
+	require.NoError(t, err)
+
+	expectedLabels := map[string]string{
+		"traefik.Level":       "debug",
+		"traefik.IP":          "10.0.0.1",
+		"traefik.Prefix":      "10.0.0.0/8",
+		"traefik.Trusted":     "10.0.0.0/8, 192.168.0.0/16",
+		"traefik.Size":        "123456789012345678901234567890",
+		"traefik.Levels.foo":  "warn",
+		"traefik.Levels.bar":  "info",
+		"traefik.Levels.buzz": "debug",
+	}
+	assert.Equal(t, expectedLabels, encoded)
+}
+
+func TestDecode_CustomTypes_errors(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		labels map[string]string
+	}{
+		{
+			desc:   "invalid value",
+			labels: map[string]string{"traefik.level": "foo"},
+		},
+		{
+			desc:   "invalid slice value",
+			labels: map[string]string{"traefik.trusted": "10.0.0.0/8,foo"},
+		},
+		{
+			desc:   "children",
+			labels: map[string]string{"traefik.prefix.foo": "10.0.0.0/8"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := Decode(test.labels, &Network{}, "traefik")
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Resolver resolves the references to the secrets of a scheme (ex: ${secret:name}) to their values.
+type Resolver interface {
+	Resolve(reference string) (string, error)
+}
+
+// ResolverFunc is a function used as a Resolver.
+type ResolverFunc func(reference string) (string, error)
+
+// Resolve calls f(reference).
+func (f ResolverFunc) Resolve(reference string) (string, error) {
+	return f(reference)
+}
+
+var resolvers = struct {
+	sync.RWMutex
+	byScheme map[string]Resolver
+}{byScheme: map[string]Resolver{
+	"file": ResolverFunc(resolveFile),
+}}
+
+// RegisterResolver registers the resolver of the references with the given scheme: ${scheme:reference}.
+// The "file" scheme is built in: ${file:/run/secrets/db_password} is replaced by the content of the file,
+// without its trailing newline.
+// The references are resolved by the decoders when enabled (see DecodeOpts.Resolve),
+// and the nodes including a resolved value are marked as sensitive in the decoded tree:
+// their values are redacted from the error messages, and from the encoders given their paths (see Node.SensitivePaths).
+// Registering an already registered scheme replaces its resolver.
+func RegisterResolver(scheme string, resolver Resolver) {
+	if scheme == "" || resolver == nil {
+		panic("parser: RegisterResolver with an empty scheme or a nil resolver")
+	}
+
+	resolvers.Lock()
+	defer resolvers.Unlock()
+
+	resolvers.byScheme[strings.ToLower(scheme)] = resolver
+}
+
+func resolve(scheme, reference string) (string, error) {
+	resolvers.RLock()
+	resolver, ok := resolvers.byScheme[strings.ToLower(scheme)]
+	resolvers.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("unknown resolver scheme: %s", scheme)
+	}
+
+	value, err := resolver.Resolve(reference)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ${%s:%s}: %w", scheme, reference, err)
+	}
+
+	return value, nil
+}
+
+func resolveFile(filePath string) (string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	value := strings.TrimSuffix(string(content), "\n")
+
+	return strings.TrimSuffix(value, "\r"), nil
+}
@@ -0,0 +1,174 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// suggestFieldName returns the name of the field of rType which is the closest to name, if close enough.
+func (m metadata) suggestFieldName(rType reflect.Type, name string) string {
+	if strings.HasPrefix(name, "[") {
+		return ""
+	}
+
+	maxDistance := len(name) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	var suggestion string
+	for _, candidate := range m.getFieldNames(rType) {
+		distance := levenshtein(strings.ToLower(name), strings.ToLower(candidate))
+		if distance <= maxDistance {
+			suggestion = candidate
+			maxDistance = distance - 1
+		}
+	}
+
+	return suggestion
+}
+
+// getFieldNames returns the names of the fields of rType, as they are expected in the configuration.
+func (m metadata) getFieldNames(rType reflect.Type) []string {
+	if rType.Kind() == reflect.Pointer {
+		rType = rType.Elem()
+	}
+
+	if rType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < rType.NumField(); i++ {
+		cField := rType.Field(i)
+
+		if !IsExported(cField) || cField.Tag.Get(m.TagName) == "-" {
+			continue
+		}
+
+		if cField.Anonymous && cField.Type.Kind() == reflect.Struct {
+			names = append(names, m.getFieldNames(cField.Type)...)
+			continue
+		}
+
+		names = append(names, m.getName(cField.Name, cField.Tag))
+	}
+
+	return names
+}
+
+// getName returns the name of a field, as it is expected in the configuration.
+func (m metadata) getName(fieldName string, tag reflect.StructTag) string {
+	if name := tag.Get(TagLabelSliceAsStruct); m.AllowSliceAsStruct && name != "" {
+		return name
+	}
+
+	return LowerCamelCase(fieldName)
+}
+
+// getCanonicalPath returns the path of the target node, built with the names of the fields.
+func (m metadata) getCanonicalPath(node, target *Node, path string) (string, bool) {
+	if node.FieldName != "" {
+		path = childPath(path, m.getName(node.FieldName, node.Tag))
+	} else {
+		path = childPath(path, node.Name)
+	}
+
+	if node == target {
+		return path, true
+	}
+
+	for _, child := range node.Children {
+		if p, ok := m.getCanonicalPath(child, target, path); ok {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+// completeSuggestions replaces the suggested field names by the full suggested paths.
+func (m metadata) completeSuggestions(root *Node, err error) {
+	for _, dErr := range AsDecodeErrors(err) {
+		if dErr.parent == nil {
+			continue
+		}
+
+		if p, ok := m.getCanonicalPath(root, dErr.parent, ""); ok && dErr.Suggestion != "" {
+			dErr.Suggestion = childPath(p, dErr.Suggestion)
+		} else {
+			dErr.Suggestion = ""
+		}
+
+		dErr.parent = nil
+	}
+}
+
+// LowerCamelCase converts a field name to lower camel case, acronyms included (ex: TLS -> tls, HTTPClient -> httpClient).
+func LowerCamelCase(name string) string {
+	runes := []rune(name)
+
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+
+	switch {
+	case upper == 0:
+		return name
+	case upper == 1 || upper == len(runes):
+		// nothing to do.
+	case !unicode.IsLower(runes[upper]):
+		// acronym followed by a digit or something else than a letter.
+	default:
+		// the last upper case letter is the first letter of the next word.
+		upper--
+	}
+
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLowerCamelCase(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{name: "foo", expected: "foo"},
+		{name: "Foo", expected: "foo"},
+		{name: "EntryPoints", expected: "entryPoints"},
+		{name: "TLS", expected: "tls"},
+		{name: "HTTPClient", expected: "httpClient"},
+		{name: "URL", expected: "url"},
+		{name: "HTTP2Enabled", expected: "http2Enabled"},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, LowerCamelCase(test.name))
+		})
+	}
+}
+
+func Test_levenshtein(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "", b: "", expected: 0},
+		{a: "", b: "foo", expected: 3},
+		{a: "address", b: "address", expected: 0},
+		{a: "adress", b: "address", expected: 1},
+		{a: "entrypont", b: "entrypoints", expected: 2},
+		{a: "kitten", b: "sitting", expected: 3},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.a+"/"+test.b, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, levenshtein(test.a, test.b))
+			assert.Equal(t, test.expected, levenshtein(test.b, test.a))
+		})
+	}
+}
+
+func TestDecode_suggestion(t *testing.T) {
+	type EntryPoint struct {
+		Address string
+	}
+
+	type TLSConfig struct {
+		InsecureSkipVerify bool
+	}
+
+	type Configuration struct {
+		EntryPoints map[string]*EntryPoint
+		TLS         *TLSConfig
+		Servers     []EntryPoint
+		Secret      string `label:"-"`
+	}
+
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected string
+	}{
+		{
+			desc:     "map entry field",
+			labels:   map[string]string{"traefik.entrypoints.web.adress": ":80"},
+			expected: "traefik.entryPoints.web.address",
+		},
+		{
+			desc:     "root field",
+			labels:   map[string]string{"traefik.entrypont.web.address": ":80"},
+			expected: "traefik.entryPoints",
+		},
+		{
+			desc:     "acronym",
+			labels:   map[string]string{"traefik.tls.insecureskipverfy": "true"},
+			expected: "traefik.tls.insecureSkipVerify",
+		},
+		{
+			desc:     "slice element field",
+			labels:   map[string]string{"traefik.servers[0].addres": "foo"},
+			expected: "traefik.servers[0].address",
+		},
+		{
+			desc:   "too far",
+			labels: map[string]string{"traefik.foo": "bar"},
+		},
+		{
+			desc:   "ignored field",
+			labels: map[string]string{"traefik.secrets": "bar"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := Decode(test.labels, &Configuration{}, DefaultRootName)
+
+			dErrs := AsDecodeErrors(err)
+			if assert.Len(t, dErrs, 1) {
+				assert.Equal(t, test.expected, dErrs[0].Suggestion)
+			}
+		})
+	}
+}
+
+func TestDecodeWithOpts_suggestionAggregateErrors(t *testing.T) {
+	type Configuration struct {
+		Name    string
+		Address string
+	}
+
+	labels := map[string]string{
+		"traefik.nme":    "foo",
+		"traefik.adress": "bar",
+	}
+
+	err := DecodeWithOpts(labels, &Configuration{}, DefaultRootName, DecodeOpts{AggregateErrors: true})
+
+	var suggestions []string
+	for _, dErr := range AsDecodeErrors(err) {
+		suggestions = append(suggestions, dErr.Suggestion)
+	}
+
+	assert.Equal(t, []string{"traefik.address", "traefik.name"}, suggestions)
+
+	expected := `traefik.adress (label): field not found, node: adress, did you mean "traefik.address"?
+traefik.nme (label): field not found, node: nme, did you mean "traefik.name"?`
+	assert.EqualError(t, err, expected)
+}
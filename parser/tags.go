@@ -1,5 +1,10 @@
 package parser
 
+import (
+	"reflect"
+	"strings"
+)
+
 const (
 	// TagLabel allows to apply a custom behavior.
 	// - "allowEmpty": allows the creation of a type that is supposed to have children
@@ -21,6 +26,43 @@ const (
 	// - "-": ignore the field.
 	TagDescription = "description"
 
+	// TagDefault is the default value of the field, applied when the parent struct is instantiated.
+	// The value is parsed as the value of a label (ex: "42", "1s", "foo,bar").
+	// The values defined by a SetDefaults method take precedence.
+	TagDefault = "default"
+
+	// TagValidate is the comma separated list of the validation rules of the field, applied after the decoding:
+	// - "required": the value must be defined (not zero, not empty).
+	// - "nonempty": the string, slice, or map must not be empty, if defined.
+	// - "min=<value>", "max=<value>": the bounds of a number (parsed as the field value, ex: "1s" for a duration),
+	// or of the length of a string, slice, or map.
+	// - "oneof=<value> <value>...": the allowed values, separated by spaces.
+	// - "pattern=<regexp>": the regular expression that the value must match (always the last rule).
+	TagValidate = "paerser-validate"
+
+	// TagAlias is the comma separated list of the deprecated names of the field.
+	// The deprecated names are accepted by the decoders, but only the current name is used by the encoders.
+	TagAlias = "alias"
+
+	// TagMerge is the strategy used by MergeNodes to merge the values of the field:
+	// - for a slice: "replace" (default), "append" (the elements are appended to the base elements),
+	// or "index" (the elements are merged by index).
+	// - for a map: "deep" (default, the entries are merged recursively), or "replace".
+	TagMerge = "merge"
+
+	// TagSensitive marks the value of the field as sensitive (ex: a password or a token).
+	// - "true": the value, and the values of the children, are replaced by RedactedValue when they are reported:
+	// by the encoders (labels, flags, environment variables, files), the help, the diffs, and the decode errors.
+	TagSensitive = "sensitive"
+
 	// TagLabelAllowEmpty is related to TagLabel.
 	TagLabelAllowEmpty = "allowEmpty"
 )
+
+// RedactedValue is the value reported instead of a sensitive value.
+const RedactedValue = "<redacted>"
+
+// IsSensitive returns true if the tag marks the value of the field as sensitive.
+func IsSensitive(tag reflect.StructTag) bool {
+	return strings.EqualFold(tag.Get(TagSensitive), "true")
+}
@@ -0,0 +1,344 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Validator is implemented by the types which validate their own values.
+// The Validate methods are called from the leaves to the root, after the validation of the fields.
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError is the error returned when a validation rule (see TagValidate) or a Validate method fails.
+type ValidationError struct {
+	// Rule is the failed validation rule, empty if the error comes from a Validate method.
+	Rule string
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Rule == "" {
+		return "validation failed: " + e.Err.Error()
+	}
+
+	return fmt.Sprintf("validation failed (%s): %v", e.Rule, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate validates element, a pointer to a struct,
+// by applying the validation rules of the fields (see TagValidate) and by calling the Validate methods.
+// All the failures are returned (DecodeErrors) sorted by path,
+// the paths are built from rootName and the names of the fields.
+func Validate(element interface{}, rootName string) error {
+	if element == nil {
+		return nil
+	}
+
+	var errs DecodeErrors
+	validateValue(reflect.ValueOf(element), rootName, false, &errs)
+
+	sortDecodeErrors(errs)
+
+	return errs.errorOrNil()
+}
+
+// validateValue validates the value located at path, the values of the errors are redacted if the value is sensitive.
+func validateValue(value reflect.Value, path string, sensitive bool, errs *DecodeErrors) {
+	if !value.IsValid() || IsCustomType(value.Type()) {
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !value.IsNil() {
+			validateValue(value.Elem(), path, sensitive, errs)
+		}
+
+	case reflect.Struct:
+		validateStruct(value, path, sensitive, errs)
+
+		if err := callValidate(value); err != nil {
+			*errs = append(*errs, &DecodeError{Path: path, Err: &ValidationError{Err: err}})
+		}
+
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			validateValue(value.Index(i), childPath(path, "["+strconv.Itoa(i)+"]"), sensitive, errs)
+		}
+
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			validateValue(value.MapIndex(key), childPath(path, key.String()), sensitive, errs)
+		}
+	}
+}
+
+func validateStruct(value reflect.Value, path string, sensitive bool, errs *DecodeErrors) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		if !IsExported(field) || field.Tag.Get(TagLabel) == "-" {
+			continue
+		}
+
+		if field.Anonymous {
+			validateValue(value.Field(i), path, sensitive, errs)
+			continue
+		}
+
+		p := childPath(path, LowerCamelCase(field.Name))
+		fieldSensitive := sensitive || IsSensitive(field.Tag)
+
+		for _, rule := range getRules(field.Tag.Get(TagValidate)) {
+			if err := checkRule(value.Field(i), rule); err != nil {
+				dErr := &DecodeError{
+					Path:  p,
+					Value: getRawValue(value.Field(i)),
+					Err:   &ValidationError{Rule: rule, Err: err},
+				}
+
+				if fieldSensitive {
+					redactDecodeError(dErr)
+				}
+
+				*errs = append(*errs, dErr)
+			}
+		}
+
+		validateValue(value.Field(i), p, fieldSensitive, errs)
+	}
+}
+
+// callValidate calls the Validate method of the struct value, if any.
+func callValidate(value reflect.Value) error {
+	if !value.CanAddr() {
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		value = ptr.Elem()
+	}
+
+	if validator, ok := value.Addr().Interface().(Validator); ok {
+		return validator.Validate()
+	}
+
+	return nil
+}
+
+// getRules splits the validation rules, a pattern is always the last rule because it can contain commas.
+func getRules(tag string) []string {
+	var rules []string
+
+	parts := strings.Split(tag, ",")
+	for i, part := range parts {
+		rule := strings.TrimSpace(part)
+		if strings.HasPrefix(rule, "pattern=") {
+			rules = append(rules, strings.Join(append([]string{rule}, parts[i+1:]...), ","))
+			break
+		}
+
+		if rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// GetConstraints returns a human readable representation of the validation rules of a field.
+func GetConstraints(tag reflect.StructTag) string {
+	return strings.Join(GetValidationRules(tag), ", ")
+}
+
+// GetValidationRules returns the validation rules of a field (ex: "required", "min=1", "pattern=^a,b$").
+func GetValidationRules(tag reflect.StructTag) []string {
+	return getRules(tag.Get(TagValidate))
+}
+
+func checkRule(value reflect.Value, rule string) error {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	if name == "required" {
+		if isEmptyValue(value) {
+			return errors.New("the value is required")
+		}
+		return nil
+	}
+
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	switch name {
+	case "nonempty":
+		if !hasLength(value) {
+			return fmt.Errorf("unsupported type: %s", value.Type())
+		}
+
+		if value.Len() == 0 {
+			return errors.New("the value must not be empty")
+		}
+		return nil
+
+	case "min", "max":
+		return checkBound(value, name, arg)
+
+	case "oneof":
+		return checkEach(value, func(raw string) error {
+			for _, v := range strings.Fields(arg) {
+				if raw == v {
+					return nil
+				}
+			}
+
+			return fmt.Errorf("%q must be one of: %s", raw, strings.Join(strings.Fields(arg), ", "))
+		})
+
+	case "pattern":
+		exp, err := regexp.Compile(arg)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+
+		return checkEach(value, func(raw string) error {
+			if !exp.MatchString(raw) {
+				return fmt.Errorf("%q must match the pattern %s", raw, arg)
+			}
+			return nil
+		})
+
+	default:
+		return fmt.Errorf("unknown rule: %s", name)
+	}
+}
+
+// checkBound checks the min or max rule: the bound is a length for the strings, slices and maps,
+// or a value parsed as the value of the field (ex: a duration).
+func checkBound(value reflect.Value, name, arg string) error {
+	var cmp int
+
+	if hasLength(value) {
+		bound, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid bound %q: %w", arg, err)
+		}
+
+		cmp = compare(float64(value.Len()), float64(bound))
+	} else {
+		bound := reflect.New(value.Type()).Elem()
+
+		err := newFiller(FillerOpts{}).fill(bound, &Node{Value: arg}, "")
+		if err != nil {
+			return fmt.Errorf("invalid bound %q: %w", arg, err)
+		}
+
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			cmp = compare(float64(value.Int()), float64(bound.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			cmp = compare(float64(value.Uint()), float64(bound.Uint()))
+		case reflect.Float32, reflect.Float64:
+			cmp = compare(value.Float(), bound.Float())
+		default:
+			return fmt.Errorf("unsupported type: %s", value.Type())
+		}
+	}
+
+	if name == "min" && cmp < 0 {
+		return fmt.Errorf("the value must be greater than or equal to %s", arg)
+	}
+
+	if name == "max" && cmp > 0 {
+		return fmt.Errorf("the value must be less than or equal to %s", arg)
+	}
+
+	return nil
+}
+
+// checkEach applies check to the raw representation of the value, or of each element of a slice.
+func checkEach(value reflect.Value, check func(raw string) error) error {
+	if value.Kind() == reflect.Slice && !IsCustomType(value.Type()) {
+		for i := 0; i < value.Len(); i++ {
+			if err := check(getRawValue(value.Index(i))); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return check(getRawValue(value))
+}
+
+// getRawValue returns the raw representation of a single value.
+func getRawValue(value reflect.Value) string {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	if isCustomMarshaler(value.Type()) {
+		raw, err := getCustomValue(value)
+		if err == nil {
+			return raw
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Interface:
+		return ""
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+func isEmptyValue(value reflect.Value) bool {
+	if hasLength(value) {
+		return value.Len() == 0
+	}
+
+	return value.IsZero()
+}
+
+func hasLength(value reflect.Value) bool {
+	if IsCustomType(value.Type()) {
+		return false
+	}
+
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+func compare(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,225 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ValidatedEntryPoint struct {
+	Address string        `paerser-validate:"required,pattern=^:[0-9]{2,5}$"`
+	Timeout time.Duration `paerser-validate:"min=1s,max=1m"`
+}
+
+type ValidatedConfig struct {
+	Name        string                          `paerser-validate:"nonempty,max=8"`
+	Level       LogLevel                        `paerser-validate:"oneof=info warn"`
+	Port        *int                            `paerser-validate:"min=1,max=65535"`
+	Methods     []string                        `paerser-validate:"min=1,oneof=GET POST"`
+	EntryPoints map[string]*ValidatedEntryPoint `paerser-validate:"required"`
+	Servers     []ValidatedServer
+}
+
+type ValidatedServer struct {
+	URL    string
+	Weight int
+}
+
+func (s *ValidatedServer) Validate() error {
+	if s.URL == "" && s.Weight > 0 {
+		return errors.New("a weighted server must have an URL")
+	}
+
+	return nil
+}
+
+type ValidatedRoot struct {
+	Foo string
+	Bar string
+}
+
+func (r ValidatedRoot) Validate() error {
+	if r.Foo == "" && r.Bar == "" {
+		return errors.New("foo or bar must be defined")
+	}
+
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	port := 8080
+	invalidPort := 0
+
+	testCases := []struct {
+		desc     string
+		element  interface{}
+		expected map[string]string
+	}{
+		{
+			desc: "valid",
+			element: &ValidatedConfig{
+				Name:    "foo",
+				Level:   LogLevelWarn,
+				Port:    &port,
+				Methods: []string{"GET"},
+				EntryPoints: map[string]*ValidatedEntryPoint{
+					"web": {Address: ":80", Timeout: time.Second},
+				},
+				Servers: []ValidatedServer{{URL: "http://localhost", Weight: 1}},
+			},
+		},
+		{
+			desc: "invalid",
+			element: &ValidatedConfig{
+				Name:    "foobarbaz",
+				Level:   LogLevelDebug,
+				Port:    &invalidPort,
+				Methods: []string{"GET", "PUT"},
+				EntryPoints: map[string]*ValidatedEntryPoint{
+					"web":       {Address: ":80"},
+					"websecure": {Address: "443", Timeout: time.Hour},
+				},
+				Servers: []ValidatedServer{{URL: "http://localhost"}, {Weight: 1}},
+			},
+			expected: map[string]string{
+				"traefik.entryPoints.web.timeout":       "min=1s",
+				"traefik.entryPoints.websecure.address": "pattern=^:[0-9]{2,5}$",
+				"traefik.entryPoints.websecure.timeout": "max=1m",
+				"traefik.level":                         "oneof=info warn",
+				"traefik.methods":                       "oneof=GET POST",
+				"traefik.name":                          "max=8",
+				"traefik.port":                          "min=1",
+				"traefik.servers[1]":                    "",
+			},
+		},
+		{
+			desc:    "missing values",
+			element: &ValidatedConfig{Name: "", Methods: []string{}},
+			expected: map[string]string{
+				"traefik.entryPoints": "required",
+				"traefik.methods":     "min=1",
+				"traefik.name":        "nonempty",
+			},
+		},
+		{
+			desc:     "value receiver",
+			element:  &ValidatedRoot{},
+			expected: map[string]string{"traefik": ""},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := Validate(test.element, DefaultRootName)
+			if test.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+
+			failures := map[string]string{}
+			for _, dErr := range AsDecodeErrors(err) {
+				var vErr *ValidationError
+				require.ErrorAs(t, dErr, &vErr)
+
+				failures[dErr.Path] = vErr.Rule
+			}
+
+			assert.Equal(t, test.expected, failures)
+		})
+	}
+}
+
+func Test_getRules(t *testing.T) {
+	rules := getRules("required, min=1,pattern=^a,b$")
+
+	assert.Equal(t, []string{"required", "min=1", "pattern=^a,b$"}, rules)
+}
+
+func TestDecode_validation(t *testing.T) {
+	labels := map[string]string{
+		"traefik.name":                    "foo",
+		"traefik.entrypoints.web.address": "80",
+		"traefik.servers[0].weight":       "1",
+	}
+
+	err := Decode(labels, &ValidatedConfig{}, DefaultRootName)
+	require.Error(t, err)
+
+	dErrs := AsDecodeErrors(err)
+	require.Len(t, dErrs, 1)
+	assert.Equal(t, "traefik.entryPoints.web.address", dErrs[0].Path)
+	assert.Equal(t, "label", dErrs[0].Origin)
+	assert.Equal(t, `traefik.entryPoints.web.address (label): validation failed (pattern=^:[0-9]{2,5}$): "80" must match the pattern ^:[0-9]{2,5}$`, err.Error())
+
+	err = DecodeWithOpts(labels, &ValidatedConfig{}, DefaultRootName, DecodeOpts{AggregateErrors: true})
+	require.Error(t, err)
+
+	var paths []string
+	for _, dErr := range AsDecodeErrors(err) {
+		paths = append(paths, dErr.Path)
+	}
+
+	expected := []string{
+		"traefik.entryPoints.web.address",
+		"traefik.entryPoints.web.timeout",
+		"traefik.methods",
+		"traefik.servers[0]",
+	}
+	assert.Equal(t, expected, paths)
+}
+
+func TestDecode_foreignValidateTag(t *testing.T) {
+	// the tags of the other validation libraries are ignored.
+	element := &struct {
+		Email string `validate:"required,email"`
+	}{}
+
+	err := Decode(map[string]string{"traefik.email": "foo"}, element, DefaultRootName)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", element.Email)
+}
+
+func TestValidate_sensitive(t *testing.T) {
+	type Auth struct {
+		Token string `paerser-validate:"min=8"`
+	}
+
+	element := &struct {
+		Password string `sensitive:"true" paerser-validate:"pattern=^[a-z]{8,}$"`
+		Auth     *Auth  `sensitive:"true"`
+		Name     string `paerser-validate:"max=3"`
+	}{
+		Password: "admin",
+		Auth:     &Auth{Token: "xyz"},
+		Name:     "foobar",
+	}
+
+	err := Validate(element, DefaultRootName)
+	require.Error(t, err)
+
+	dErrs := AsDecodeErrors(err)
+	require.Len(t, dErrs, 3)
+
+	assert.Equal(t, "traefik.auth.token", dErrs[0].Path)
+	assert.Equal(t, RedactedValue, dErrs[0].Value)
+	assert.Equal(t, "traefik.name", dErrs[1].Path)
+	assert.Equal(t, "foobar", dErrs[1].Value)
+	assert.Equal(t, "traefik.password", dErrs[2].Path)
+	assert.Equal(t, RedactedValue, dErrs[2].Value)
+
+	var vErr *ValidationError
+	require.True(t, errors.As(dErrs[2], &vErr))
+	assert.Equal(t, "pattern=^[a-z]{8,}$", vErr.Rule)
+
+	assert.NotContains(t, err.Error(), "admin")
+	assert.NotContains(t, err.Error(), "xyz")
+}
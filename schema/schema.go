@@ -0,0 +1,398 @@
+// Package schema implements the generation of a JSON Schema from a typed Configuration.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/traefik/paerser/generator"
+	"github.com/traefik/paerser/parser"
+	"github.com/traefik/paerser/types"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// DurationPattern is the pattern of the durations: a number of seconds, or a Go duration (ex: 10, 1.5s, 1h30m).
+const DurationPattern = `^([0-9]+|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+
+// Schema is a JSON Schema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+}
+
+// Generate generates the JSON Schema of the configuration files related to element (a pointer to a struct).
+// The fields are walked with the same rules as the file decoder:
+// the fields ignored by the file tag ("-") are skipped, and the names of the fields are in lower camel case.
+// The descriptions come from the description tag, the defaults from generator.Generate (default tags and SetDefaults),
+// except for the sensitive fields (see parser.TagSensitive),
+// the constraints from the paerser-validate tag, and the aliases are deprecated properties.
+func Generate(element interface{}) (*Schema, error) {
+	if element == nil {
+		return nil, nil
+	}
+
+	rType := reflect.TypeOf(element)
+	if rType.Kind() != reflect.Pointer || rType.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported type: %s (a pointer to a struct is expected)", rType)
+	}
+
+	// a new instance is used to get the defaults without modifying the element.
+	instance := reflect.New(rType.Elem())
+	if err := generator.GenerateWithError(instance.Interface()); err != nil {
+		return nil, err
+	}
+
+	schema, err := getSchema(instance.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	schema.Schema = Draft
+
+	return schema, nil
+}
+
+// Encode generates the JSON Schema of the configuration files related to element, as indented JSON.
+func Encode(element interface{}) ([]byte, error) {
+	schema, err := Generate(element)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func getSchema(rValue reflect.Value) (*Schema, error) {
+	rType := rValue.Type()
+
+	if rType.Kind() == reflect.Pointer {
+		if rValue.IsNil() {
+			return getSchema(reflect.New(rType.Elem()).Elem())
+		}
+		return getSchema(rValue.Elem())
+	}
+
+	if rType == reflect.TypeOf(types.Duration(0)) || rType == reflect.TypeOf(time.Duration(0)) {
+		return &Schema{Type: []string{"string", "integer"}, Pattern: DurationPattern}, nil
+	}
+
+	if parser.IsCustomType(rType) {
+		return &Schema{Type: "string"}, nil
+	}
+
+	switch rType.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: float64Ptr(0)}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+
+	case reflect.Struct:
+		return getStructSchema(rValue)
+
+	case reflect.Map:
+		if rType.Elem().Kind() == reflect.Interface {
+			// raw map
+			return &Schema{Type: "object", AdditionalProperties: true}, nil
+		}
+
+		// the generator creates an entry with the map name placeholder.
+		elem := rValue.MapIndex(reflect.ValueOf(parser.MapNamePlaceholder))
+		if !elem.IsValid() {
+			elem = reflect.New(rType.Elem()).Elem()
+		}
+
+		items, err := getSchema(elem)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Type: "object", AdditionalProperties: items}, nil
+
+	case reflect.Slice:
+		// the generator creates one element in the slices of structs.
+		elem := reflect.New(rType.Elem()).Elem()
+		if rValue.Len() > 0 {
+			elem = rValue.Index(0)
+		}
+
+		items, err := getSchema(elem)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Type: "array", Items: items}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", rType)
+	}
+}
+
+func getStructSchema(rValue reflect.Value) (*Schema, error) {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	if err := addProperties(schema, rValue); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+func addProperties(schema *Schema, rValue reflect.Value) error {
+	for i := 0; i < rValue.NumField(); i++ {
+		field := rValue.Type().Field(i)
+		fieldValue := rValue.Field(i)
+
+		if !parser.IsExported(field) || field.Tag.Get(parser.TagFile) == "-" {
+			continue
+		}
+
+		if field.Anonymous && !parser.IsCustomType(field.Type) &&
+			(field.Type.Kind() == reflect.Struct || field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct) {
+			if fieldValue.Kind() == reflect.Pointer {
+				if fieldValue.IsNil() {
+					fieldValue = reflect.New(field.Type.Elem())
+				}
+				fieldValue = fieldValue.Elem()
+			}
+
+			if err := addProperties(schema, fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+
+		property, err := getSchema(fieldValue)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+
+		if description := field.Tag.Get(parser.TagDescription); description != "-" {
+			property.Description = description
+		}
+
+		// the default values of the sensitive fields are not published.
+		if !parser.IsSensitive(field.Tag) {
+			property.Default, err = getDefault(fieldValue)
+			if err != nil {
+				return fmt.Errorf("%s: %w", field.Name, err)
+			}
+		}
+
+		name := parser.LowerCamelCase(field.Name)
+
+		required, err := addConstraints(property, field)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = property
+
+		for _, alias := range parser.GetAliases(field) {
+			deprecated := *property
+			deprecated.Deprecated = true
+			schema.Properties[parser.LowerCamelCase(alias)] = &deprecated
+		}
+	}
+
+	return nil
+}
+
+// getDefault returns the default value of a leaf field, nil if the value is zero.
+func getDefault(rValue reflect.Value) (interface{}, error) {
+	if rValue.Kind() == reflect.Pointer {
+		if rValue.IsNil() {
+			return nil, nil
+		}
+		rValue = rValue.Elem()
+	}
+
+	if rValue.IsZero() {
+		return nil, nil
+	}
+
+	if parser.IsCustomType(rValue.Type()) {
+		value, ok, err := parser.EncodeCustomValue(rValue)
+		if err != nil || ok {
+			return value, err
+		}
+	}
+
+	switch rValue.Kind() {
+	case reflect.String:
+		return rValue.String(), nil
+	case reflect.Bool:
+		return rValue.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rValue.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rValue.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rValue.Float(), nil
+	case reflect.Slice:
+		elemType := rValue.Type().Elem()
+		if elemType.Kind() == reflect.Pointer {
+			elemType = elemType.Elem()
+		}
+
+		if elemType.Kind() == reflect.Struct && !parser.IsCustomType(elemType) || rValue.Len() == 0 {
+			return nil, nil
+		}
+
+		var values []interface{}
+		for i := 0; i < rValue.Len(); i++ {
+			value, err := getDefault(rValue.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+		}
+
+		return values, nil
+	default:
+		return nil, nil
+	}
+}
+
+// addConstraints adds the validation rules of the field (see parser.TagValidate) to its schema.
+// It returns true if the field is required.
+func addConstraints(schema *Schema, field reflect.StructField) (bool, error) {
+	var required bool
+
+	for _, rule := range parser.GetValidationRules(field.Tag) {
+		name, arg, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			required = true
+
+		case "nonempty":
+			setLength(schema, "min", 1)
+
+		case "min", "max":
+			if schema.Type == "integer" || schema.Type == "number" {
+				bound, err := strconv.ParseFloat(arg, 64)
+				if err != nil {
+					return false, fmt.Errorf("invalid rule %q: %w", rule, err)
+				}
+
+				if name == "min" {
+					schema.Minimum = &bound
+				} else {
+					schema.Maximum = &bound
+				}
+				continue
+			}
+
+			length, err := strconv.Atoi(arg)
+			if err != nil {
+				// ex: the bounds of a duration are not supported.
+				continue
+			}
+
+			setLength(schema, name, length)
+
+		case "oneof":
+			target := schema
+			if schema.Items != nil {
+				target = schema.Items
+			}
+
+			for _, value := range strings.Fields(arg) {
+				target.Enum = append(target.Enum, getEnumValue(target, value))
+			}
+
+		case "pattern":
+			if schema.Items != nil {
+				schema.Items.Pattern = arg
+			} else {
+				schema.Pattern = arg
+			}
+		}
+	}
+
+	return required, nil
+}
+
+func setLength(schema *Schema, bound string, length int) {
+	var minLength, maxLength **int
+
+	switch schema.Type {
+	case "string":
+		minLength, maxLength = &schema.MinLength, &schema.MaxLength
+	case "array":
+		minLength, maxLength = &schema.MinItems, &schema.MaxItems
+	case "object":
+		minLength, maxLength = &schema.MinProperties, &schema.MaxProperties
+	default:
+		return
+	}
+
+	if bound == "min" {
+		*minLength = &length
+	} else {
+		*maxLength = &length
+	}
+}
+
+func getEnumValue(schema *Schema, value string) interface{} {
+	switch schema.Type {
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+
+	return value
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/paerser/types"
+)
+
+type Configuration struct {
+	Name     string                 `description:"Name of the instance." paerser-validate:"required"`
+	Port     uint16                 `description:"Port to listen on." default:"8080" paerser-validate:"min=1,max=65535"`
+	Debug    bool                   `description:"Enable the debug mode."`
+	Ratio    float64                `description:"Ratio." paerser-validate:"max=1"`
+	Timeout  types.Duration         `description:"Timeout of the requests."`
+	Delay    time.Duration          `description:"Delay."`
+	Level    string                 `description:"Log level." paerser-validate:"oneof=debug info error"`
+	Hosts    []string               `description:"Hosts." paerser-validate:"nonempty,pattern=^[a-z.]+$" alias:"Domains"`
+	Servers  []Server               `description:"Backend servers."`
+	Routes   map[string]*Server     `description:"Routes by name."`
+	Labels   map[string]string      `description:"Labels."`
+	Plugins  map[string]interface{} `description:"Plugins configuration."`
+	Internal string                 `file:"-"`
+	TLS      *TLS                   `file:"allowEmpty"`
+}
+
+func (c *Configuration) SetDefaults() {
+	c.Name = "traefik"
+	c.Timeout = types.Duration(30 * time.Second)
+	c.Hosts = []string{"localhost"}
+}
+
+type Server struct {
+	URL    string `description:"URL of the server."`
+	Weight *int   `description:"Weight of the server." default:"1"`
+}
+
+type TLS struct {
+	Base
+	Insecure bool
+}
+
+type Base struct {
+	CA string `description:"Certificate authority."`
+}
+
+func TestEncode(t *testing.T) {
+	content, err := Encode(&Configuration{})
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile("./fixtures/configuration.json")
+	require.NoError(t, err)
+
+	assert.JSONEq(t, string(expected), string(content))
+}
+
+func TestGenerate(t *testing.T) {
+	element := &Configuration{}
+
+	schema, err := Generate(element)
+	require.NoError(t, err)
+
+	assert.Equal(t, Draft, schema.Schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"name"}, schema.Required)
+
+	// the element is not modified.
+	assert.Equal(t, &Configuration{}, element)
+}
+
+func TestGenerate_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		element  interface{}
+		expected string
+	}{
+		{
+			desc:     "not a pointer",
+			element:  Configuration{},
+			expected: "unsupported type: schema.Configuration (a pointer to a struct is expected)",
+		},
+		{
+			desc: "unsupported field type",
+			element: &struct {
+				Fn func()
+			}{},
+			expected: "Fn: unsupported type: func()",
+		},
+		{
+			desc: "invalid rule",
+			element: &struct {
+				Port int `paerser-validate:"min=a"`
+			}{},
+			expected: `Port: invalid rule "min=a": strconv.ParseFloat: parsing "a": invalid syntax`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Generate(test.element)
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestGenerate_nil(t *testing.T) {
+	schema, err := Generate(nil)
+	require.NoError(t, err)
+
+	assert.Nil(t, schema)
+}
+
+func TestGenerate_sensitive(t *testing.T) {
+	element := &struct {
+		Password string `description:"Password of the admin." sensitive:"true" default:"admin"`
+		User     string `description:"Name of the admin." default:"root"`
+	}{}
+
+	schema, err := Generate(element)
+	require.NoError(t, err)
+
+	require.Contains(t, schema.Properties, "password")
+	assert.Equal(t, "Password of the admin.", schema.Properties["password"].Description)
+	assert.Nil(t, schema.Properties["password"].Default)
+
+	require.Contains(t, schema.Properties, "user")
+	assert.Equal(t, "root", schema.Properties["user"].Default)
+}